@@ -0,0 +1,121 @@
+package main
+
+import "fmt"
+
+// Binding edges of the booklet
+const (
+	BindingLeft  = "left"  // Side-bound, leaves turn right to left (LTR books)
+	BindingRight = "right" // Side-bound, leaves turn left to right (RTL books)
+	BindingTop   = "top"   // Top-bound, leaves flip up like a calendar or notepad
+)
+
+var validBindings = []string{BindingLeft, BindingRight, BindingTop}
+
+// bindingDirection returns the page order a binding edge is imposed in
+//
+// Top-bound leaves flip up, so the pages follow each other as in an LTR book.
+func bindingDirection(binding string) string {
+	if binding == BindingRight {
+		return "RTL"
+	}
+	return "LTR"
+}
+
+// sideBinding returns the side binding edge of a reading direction
+func sideBinding(direction string) string {
+	if direction == "RTL" {
+		return BindingRight
+	}
+	return BindingLeft
+}
+
+// resolveBinding settles the binding edge and the reading direction
+//
+// The binding edge decides the page order and the reading direction keeps
+// /Direction and the running heads, so a top-bound RTL book stays RTL. A side
+// binding without an explicit direction implies one; an explicit direction
+// that reads against the bound side is an error. Without a binding edge the
+// book is bound on the side its reading direction calls for.
+func resolveBinding(config *BookletConfig) error {
+	if config.Binding != "" && config.Binding != BindingTop && !config.DirectionSet {
+		config.ReadingDirection = bindingDirection(config.Binding)
+	}
+	err := resolveDirection(config)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case config.Binding == "":
+		config.Binding = sideBinding(config.ReadingDirection)
+	case config.Binding != BindingTop && config.ReadingDirection != bindingDirection(config.Binding):
+		return fmt.Errorf("%s binding imposes pages in %s order, but the reading direction is %s; use -binding %s",
+			config.Binding, bindingDirection(config.Binding), config.ReadingDirection, sideBinding(config.ReadingDirection))
+	case config.Binding == BindingTop:
+		fmt.Printf("Binding on the top edge, imposing pages in %s order for a %s book\n", bindingDirection(config.Binding), config.ReadingDirection)
+	}
+	return nil
+}
+
+// foldIsVertical reports whether the fold runs vertically through the booklet page
+func foldIsVertical(binding string) bool {
+	return binding != BindingTop
+}
+
+// sheetIsLandscape reports whether the imposed sheet is landscape for the layout and binding
+//
+// Top-bound booklet pages stack their two slots above and below the fold,
+// which turns the sheet of every n-up layout.
+func sheetIsLandscape(pagesPerSheet int, binding string) bool {
+	return isLandscapeSheet(pagesPerSheet) == foldIsVertical(binding)
+}
+
+// bindingSlotSize returns the size of one page slot, landscape for top-bound booklets
+func bindingSlotSize(sheetWidth, sheetHeight float64, pagesPerSheet int, binding string) (float64, float64) {
+	width, height := slotSize(sheetWidth, sheetHeight, pagesPerSheet)
+	if !foldIsVertical(binding) {
+		return height, width
+	}
+	return width, height
+}
+
+// foldLength returns the length of the binding fold of one booklet page
+func foldLength(sheetWidth, sheetHeight float64, pagesPerSheet int, binding string) float64 {
+	width, height := bindingSlotSize(sheetWidth, sheetHeight, pagesPerSheet, binding)
+	if !foldIsVertical(binding) {
+		return width
+	}
+	return height
+}
+
+// fitsSlot reports whether a page has the orientation of its slot, so it needs no auto-rotation
+func fitsSlot(page PageInfo, binding string) bool {
+	return isLandscape(page) != foldIsVertical(binding)
+}
+
+// bookletOptions returns the pdfcpu booklet description for the layout and binding
+//
+// Side-bound pages run across the sheet in reading order; top-bound pages run
+// down it and are bound on the short edge.
+func bookletOptions(nsections int, binding string) string {
+	orientation := "rd"
+	switch binding {
+	case BindingRight:
+		orientation = "ld"
+	case BindingTop:
+		orientation = "dr"
+	}
+	options := fmt.Sprintf("multifolio:on, foliosize:%d, g:off, ma:5, border:on, bgcol:#beded9, or:%s", nsections, orientation)
+	if binding == BindingTop {
+		options += ", binding:short"
+	}
+	return options
+}
+
+// uprightFlipEdge returns the duplex flip edge that prints the backs without a turn
+func uprightFlipEdge(pagesPerSheet int, binding string) string {
+	if backRotation(DuplexShort, pagesPerSheet, binding) == 0 {
+		return DuplexShort
+	}
+	return DuplexLong
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestResolveBinding(t *testing.T) {
+	tests := []struct {
+		binding           string
+		direction         string
+		directionSet      bool
+		expectedBinding   string
+		expectedDirection string
+	}{
+		{"", "RTL", true, BindingRight, "RTL"},
+		{"", "LTR", false, BindingLeft, "LTR"},
+		{BindingLeft, "RTL", false, BindingLeft, "LTR"},
+		{BindingRight, "LTR", false, BindingRight, "RTL"},
+		{BindingLeft, "LTR", true, BindingLeft, "LTR"},
+		{BindingTop, "RTL", true, BindingTop, "RTL"},
+		{BindingTop, "LTR", false, BindingTop, "LTR"},
+	}
+
+	for _, test := range tests {
+		config := &BookletConfig{InputFile: "test.pdf", Binding: test.binding, ReadingDirection: test.direction, DirectionSet: test.directionSet}
+		if err := resolveBinding(config); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if config.Binding != test.expectedBinding || config.ReadingDirection != test.expectedDirection {
+			t.Errorf("For binding %q and %s, expected %s binding for an %s book, got %s for %s",
+				test.binding, test.direction, test.expectedBinding, test.expectedDirection, config.Binding, config.ReadingDirection)
+		}
+	}
+
+	// An explicit direction reading against the bound side can't be imposed
+	for _, binding := range []string{BindingLeft, BindingRight} {
+		direction := "RTL"
+		if binding == BindingRight {
+			direction = "LTR"
+		}
+		config := &BookletConfig{InputFile: "test.pdf", Binding: binding, ReadingDirection: direction, DirectionSet: true}
+		if err := resolveBinding(config); err == nil || !strings.Contains(err.Error(), "reading direction") {
+			t.Errorf("Expected a conflict error for %s binding and %s, got %v", binding, direction, err)
+		}
+	}
+}
+
+func TestBindingSlotSize(t *testing.T) {
+	for _, pagesPerSheet := range []int{1, 2, 4, 8} {
+		sideW, sideH := bindingSlotSize(a4Width, a4Height, pagesPerSheet, BindingLeft)
+		topW, topH := bindingSlotSize(a4Width, a4Height, pagesPerSheet, BindingTop)
+		if topW != sideH || topH != sideW {
+			t.Errorf("For %d-up, expected the top-bound slot to be the side slot turned, got %.1fx%.1f and %.1fx%.1f",
+				pagesPerSheet, sideW, sideH, topW, topH)
+		}
+		if sheetIsLandscape(pagesPerSheet, BindingTop) == sheetIsLandscape(pagesPerSheet, BindingLeft) {
+			t.Errorf("For %d-up, expected the top-bound sheet to be turned", pagesPerSheet)
+		}
+		// The fold is the same line of the paper either way
+		if foldLength(a4Width, a4Height, pagesPerSheet, BindingTop) != foldLength(a4Width, a4Height, pagesPerSheet, BindingLeft) {
+			t.Errorf("For %d-up, expected the same fold length for both bindings", pagesPerSheet)
+		}
+	}
+}
+
+func TestTopBindingBackRotation(t *testing.T) {
+	testCases := []struct {
+		mode          string
+		pagesPerSheet int
+		expected      int
+	}{
+		{DuplexManual, 1, 0},
+		{DuplexManual, 2, 180},
+		{DuplexLong, 1, 180}, // Portrait sheet flips about its vertical long edge, the leaves about the horizontal fold
+		{DuplexLong, 2, 0},
+		{DuplexShort, 1, 0},
+		{DuplexShort, 2, 180},
+		{DuplexShort, 4, 0},
+	}
+
+	for _, tc := range testCases {
+		rotation := backRotation(tc.mode, tc.pagesPerSheet, BindingTop)
+		if rotation != tc.expected {
+			t.Errorf("For top binding, mode=%s, PagesPerSheet=%d, expected rotation %d, got %d",
+				tc.mode, tc.pagesPerSheet, tc.expected, rotation)
+		}
+	}
+
+	if edge := uprightFlipEdge(1, BindingTop); edge != DuplexShort {
+		t.Errorf("Expected a 1-up top-bound sheet to flip on the short edge, got %s", edge)
+	}
+}
+
+func TestTopBindingSheetSlots(t *testing.T) {
+	cells := sheetSlots(a4Width, a4Height, 1, BindingTop)
+	if len(cells) != 1 {
+		t.Fatalf("Expected 1 booklet page, got %d", len(cells))
+	}
+	upper, lower := cells[0][0], cells[0][1]
+	if upper.X != lower.X || upper.Width != lower.Width || upper.Y <= lower.Y {
+		t.Errorf("Expected the first slot above the second, got %+v and %+v", upper, lower)
+	}
+	if math.Abs(upper.Width-a4Width) > 0.01 || math.Abs(upper.Height-a4Height/2) > 0.01 {
+		t.Errorf("Expected landscape A5 slots on a portrait A4 sheet, got %.1fx%.1f", upper.Width, upper.Height)
+	}
+}
+
+func TestTopBindingCollationMarks(t *testing.T) {
+	marks := collationMarks(4, 16, 1, a4Width, a4Height, BindingTop)
+	if len(marks) != 4 {
+		t.Fatalf("Expected 4 marks, got %d", len(marks))
+	}
+	for i, mark := range marks {
+		if math.Abs(mark.Y+mark.Height/2-a4Height/2) > 0.01 {
+			t.Errorf("Expected mark %d centered on the top fold, got y=%.1f", i+1, mark.Y)
+		}
+		if i > 0 && mark.X <= marks[i-1].X {
+			t.Errorf("Expected mark %d to step right of mark %d", i+1, i)
+		}
+	}
+}
+
+func TestTopBindingRotation(t *testing.T) {
+	pages := []PageInfo{
+		{Number: 1, Width: 595, Height: 842},
+		{Number: 2, Width: 842, Height: 595},
+	}
+	rotated := applyRotation(pages, RotateCW, "LTR", BindingTop, 0)
+	if rotated[0].Rotate == 0 {
+		t.Error("Expected the portrait page to be turned into the landscape slot")
+	}
+	if rotated[1].Rotate != 0 {
+		t.Errorf("Expected the landscape page to fit the top-bound slot, got rotation %d", rotated[1].Rotate)
+	}
+}
+
+func TestBookletOptions(t *testing.T) {
+	tests := []struct {
+		binding  string
+		expected string
+	}{
+		{BindingLeft, "or:rd"},
+		{BindingRight, "or:ld"},
+		{BindingTop, "or:dr, binding:short"},
+	}
+
+	for _, test := range tests {
+		options := bookletOptions(8, test.binding)
+		if !strings.Contains(options, "foliosize:8") || !strings.HasSuffix(options, test.expected) {
+			t.Errorf("For %s binding, expected options ending in %q, got %q", test.binding, test.expected, options)
+		}
+	}
+}
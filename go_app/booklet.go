@@ -10,27 +10,155 @@ type BookletConfig struct {
 	InputFile        string
 	OutputFile       string
 	PagesPerSheet    int
-	ReadingDirection string // "RTL" or "LTR"
+	ReadingDirection string // "RTL" or "LTR", or "auto" to detect it from the input
 	Sections         int
-	AddBlank         int // 0 or 1
+	AddBlank         int     // 0 or 1
+	Scale            string  // "fit", "fill", "actual", "first" or "largest"
+	Align            string  // Anchor of the page within its slot, e.g. "c" or "tl"
+	Rotate           string  // "off", "cw" or "ccw" for landscape pages
+	Duplex           string  // "manual", "long" or "short"
+	DuplexScope      string  // "signature" or "book" for interleaved duplex output
+	Face             string  // "up" or "down" output tray face for manual duplex
+	BackOffsetX      float64 // Back side correction in mm
+	BackOffsetY      float64
+	BackOffsetSet    bool    // -back-offset was given, even as 0,0, so it wins over the profile
+	DirectionSet     bool    // -direction was given, so a side binding must agree with it
+	BackScale        float64 // Back side scale, 0 or 1 for none
+	BackScaleSet     bool    // -back-scale was given, so it wins over the profile
+	Sheet            string  // Sheet size name, e.g. "A4" or "Letter"
+	Margin           float64 // Unprintable margin along every edge in mm
+	MarginSet        bool    // -margin was given, even as 0, so it wins over the profile
+	Printer          string  // Name of the printer profile to apply
+	ConfigFile       string  // Configuration file with the printer profiles
+	PunchTemplate    string  // Punching template PDF file, empty for none
+	NoStations       bool    // Leave the station dots off the sheets
+	Marks            string  // "folio" or "spine" section marking
+	SignatureLabels  string  // "off", "letters" or "numbers" printer's signature labels
+	SignatureLeaf    bool    // Also label the second leaf of each signature (A2)
+	BookTitle        string  // Short title printed next to the signature label
+	Cover            string  // "none", "self" or "separate" handling of the first and last pages
+	Padding          string  // Padding policy spec, empty for the bookit.sh rule
+	ChaptersRecto    bool    // Start every top-level bookmarked chapter on a recto
+	PreviewFile      string  // Fold preview PDF file, empty for none
+	DebugOverlay     bool    // Draw slot boundaries and labels on the print-ready sheets
+	Numbering        string  // Page numbering rules, empty for no page numbers
+	NumberSkip       string  // "blank" and/or "chapter" pages that don't show their number
+	RunningHeads     bool    // Stamp the title on versos and the chapter on rectos
+	HeadsFile        string  // Sidecar file with chapter titles, empty to use the outline
+	Numerals         string  // Numeral system of every stamped number, e.g. "western" or "arabic-indic"
+	FontFile         string  // Font the marks are stamped with, checked for the numeral glyphs
+	Binding          string  // "left", "right" or "top" binding edge, empty for the reading direction's side
+	Imposition       string  // Imposition template name or JSON file, empty for the -pages layout
+	CutStack         bool    // Lay out the n-up cells so each cuts into a contiguous stack
+	Repeat           bool    // Repeat every booklet side in all n-up cells, one copy per cell
+	PerfectBinding   bool    // Glue single leaves in reading order instead of folding signatures
+	Gutter           float64 // Perfect binding shift away from the glued edge in mm
+	Grind            float64 // Perfect binding spine roughening allowance in mm
+	Caliper          float64 // Paper thickness per leaf in mm, for the stack height
+	GSM              float64 // Paper weight, used when the caliper is unknown
 }
 
 // ProcessBooklet processes a PDF file to create a booklet
 func ProcessBooklet(config *BookletConfig) error {
 	fmt.Printf("Processing booklet: %s -> %s\n", config.InputFile, config.OutputFile)
-	fmt.Printf("Config: pagesPerSheet=%d, direction=%s, sections=%d, addBlank=%d\n", 
+	fmt.Printf("Config: pagesPerSheet=%d, direction=%s, sections=%d, addBlank=%d\n",
 		config.PagesPerSheet, config.ReadingDirection, config.Sections, config.AddBlank)
 
-	// Step 1: Prepare the PDF with blank pages if needed
+	// Apply the printer profile before any sheet-dependent step
+	err := loadPrinterSettings(config)
+	if err != nil {
+		return fmt.Errorf("failed to load printer profile: %w", err)
+	}
+	sheetWidth, sheetHeight, _ := sheetSize(config.Sheet)
+	err = resolveBinding(config)
+	if err != nil {
+		return err
+	}
+	// Pages are imposed in the binding's order; the reading direction still decides /Direction and heads
+	impositionDirection := bindingDirection(config.Binding)
+	template, err := loadBookletImposition(config)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the stamping font can draw the numerals before anything is written
+	if config.FontFile == "" {
+		config.FontFile = defaultFontFile
+	}
+	err = checkNumeralGlyphs(config.FontFile, config.Numerals)
+	if err != nil {
+		return err
+	}
+
+	// Read the input pages to size the signatures
+	pages, err := readPageInfo(config.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read page info: %w", err)
+	}
+	pagesPerSignature := signaturePageCount(config.Sections, config.PagesPerSheet)
+	sheetsPerSignature := 0
+	if template != nil {
+		sheetsPerSignature = signatureSheetCount(*template, config.Sections)
+		pagesPerSignature = sheetsPerSignature * template.SheetPages() / 2
+	}
+	order, plan, outline, err := bookletPageOrder(config, len(pages))
+	if err != nil {
+		return err
+	}
+	preparedPages := len(order)
+	totalSides := preparedPages / 2
+	reportPadding(plan, preparedPages)
+
+	// Step 1: Prepare the PDF with blank pages if needed, taking the cover pages out or around
 	tempFile := config.OutputFile + ".tmp"
-	err := prepareBookletPages(config.InputFile, tempFile, config.AddBlank, config.Sections, config.PagesPerSheet)
+	switch {
+	case config.Cover == CoverSeparate:
+		err = extractCoverPages(config.InputFile, coverFileName(config.OutputFile), len(pages), preparedPages)
+		if err == nil {
+			err = prepareOrderedPages(config.InputFile, tempFile, order, config.Cover)
+		}
+	case config.Cover == CoverSelf || config.Padding != "" || plan.Chapters > 0 || config.PerfectBinding:
+		err = prepareOrderedPages(config.InputFile, tempFile, order, config.Cover)
+	default:
+		err = prepareBookletPages(config.InputFile, tempFile, config.AddBlank, config.Sections, config.PagesPerSheet)
+	}
+	if err == nil && len(outline) > 0 {
+		err = updateOutline(tempFile, remapOutline(outline, order))
+	}
+	if err == nil {
+		err = writePageMarkers(tempFile, order)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to prepare booklet pages: %w", err)
 	}
 
-	// Step 2: Handle reading direction by reversing pages if RTL
+	// Step 2: Number the pages, add running heads and printer's signature labels
+	if config.Numbering != "" {
+		chapters, err := numberingChapters(config, outline)
+		if err == nil {
+			_, err = applyNumbering(tempFile, order, config.Numbering, config.NumberSkip, chapters, config.Numerals)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to number pages: %w", err)
+		}
+	}
+	if config.RunningHeads {
+		chapters, err := loadHeadsChapters(config, outline)
+		if err == nil {
+			title := headsTitle(config.BookTitle, config.InputFile)
+			err = stampRunningHeads(tempFile, runningHeads(order, chapters, title, config.ReadingDirection))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add running heads: %w", err)
+		}
+	}
+	if config.SignatureLabels != "" && config.SignatureLabels != LabelsOff && !config.PerfectBinding {
+		addSignatureLabels(tempFile, preparedPages, 2*pagesPerSignature, config)
+	}
+
+	// Step 3: Handle reading direction by reversing pages if imposed RTL; glued leaves stay in reading order
 	reversedFile := tempFile + ".rev"
-	if config.ReadingDirection == "RTL" {
+	if impositionDirection == "RTL" && !config.PerfectBinding {
 		err = handleReadingDirection(tempFile, reversedFile)
 		if err != nil {
 			return fmt.Errorf("failed to handle reading direction: %w", err)
@@ -39,30 +167,207 @@ func ProcessBooklet(config *BookletConfig) error {
 		reversedFile = tempFile // If LTR, no reversal needed
 	}
 
-	// Step 3: Create the actual booklet layout
-	err = createBooklet(reversedFile, config.OutputFile, config.PagesPerSheet)
+	// Step 4: Rotate landscape pages and apply the scaling policy for pages that don't match the slot
+	pages = applyOrderRotation(pages, order, config.Rotate, impositionDirection, config.Binding)
+	var placements []Placement
+	if config.PerfectBinding {
+		slotWidth, slotHeight := perfectSlotSize(sheetWidth, sheetHeight, config.PagesPerSheet)
+		placements = reportScaling(config.InputFile, pages, slotWidth, slotHeight, config.Scale, config.Align)
+		placements = shiftGutters(placements, order, impositionDirection, config.Gutter, config.Grind, slotWidth)
+	} else {
+		placements = applyScaling(config.InputFile, pages, sheetWidth, sheetHeight, config.PagesPerSheet, config.Binding, config.Scale, config.Align)
+	}
+
+	// Step 5: Create the actual booklet layout, or the template sheets, and the reader's preview of it
+	sides := imposeBookletSides(order, pagesPerSignature, impositionDirection)
+	var imposed []ImposedSheet
+	switch {
+	case template != nil:
+		imposed, err = imposeTemplate(*template, order, sheetsPerSignature, impositionDirection)
+		if err == nil {
+			err = writeImposedSheets(reversedFile, config.OutputFile, *template, imposed)
+		}
+	case config.PerfectBinding:
+		err = writePerfectBound(reversedFile, config.OutputFile, config.PagesPerSheet)
+	default:
+		err = createBooklet(reversedFile, config.OutputFile, config.PagesPerSheet, bookletOptions(config.Sections, config.Binding))
+	}
+	if err == nil {
+		// Only the booklet sides can be checked by verify; other layouts just record how they were made
+		var markers [][2]int
+		if template == nil && !config.PerfectBinding {
+			markers = sides
+		}
+		err = writeBookletMarkers(config.OutputFile, markerLayout(config, nil), markers)
+	}
+	if err == nil {
+		err = writeViewerPreferences(config.OutputFile, config.ReadingDirection)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create booklet: %w", err)
 	}
+	// The preview folds the built-in booklet sheets; other layouts are assembled differently
+	if config.PreviewFile != "" && template != nil {
+		fmt.Println("Warning: the fold preview is only written for the -pages layouts, not for imposition templates")
+	} else if config.PreviewFile != "" && config.PerfectBinding {
+		fmt.Println("Warning: the fold preview is only written for folded signatures, not for perfect binding")
+	} else if config.PreviewFile != "" && (config.CutStack || config.Repeat) {
+		fmt.Println("Warning: the fold preview is only written for whole sheets, not for cut-and-stack or repeat")
+	} else if config.PreviewFile != "" {
+		_, err = writeFoldPreview(config.PreviewFile, config.OutputFile, pagesPerSignature, config.PagesPerSheet, impositionDirection, pages)
+		if err == nil {
+			err = writeViewerPreferences(config.PreviewFile, config.ReadingDirection)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write fold preview: %w", err)
+		}
+	}
 
-	// Step 4: Add stations (sewing points) to the booklet
-	err = addStations(config.OutputFile, config.PagesPerSheet)
-	if err != nil {
-		return fmt.Errorf("failed to add stations: %w", err)
+	// Step 6: Add stations (sewing points) to the booklet or write them to a punching template
+	if config.PerfectBinding {
+		fmt.Printf("Perfect binding: no stations, the leaves are glued after %.1f mm is ground off the spine\n", config.Grind)
+	} else if template != nil && template.Folded {
+		// Folded sheets are sewn through their final fold, marked with the collation blocks in Step 7
+		err = addFoldMarks(config.OutputFile, *template, sheetWidth, sheetHeight, len(imposed), sheetsPerSignature, !config.NoStations, config.Marks == MarkSpine)
+		if err != nil {
+			return fmt.Errorf("failed to mark the final fold: %w", err)
+		}
+	} else if !config.NoStations {
+		err = addStations(config.OutputFile, config.PagesPerSheet, config.Binding)
+		if err != nil {
+			return fmt.Errorf("failed to add stations: %w", err)
+		}
+	}
+	switch {
+	case config.PunchTemplate == "" || config.PerfectBinding:
+	case template != nil && template.Folded:
+		_, err = writeFoldPunchTemplate(config.PunchTemplate, *template, sheetWidth, sheetHeight)
+		if err != nil {
+			return fmt.Errorf("failed to write punch template: %w", err)
+		}
+	default:
+		writePunchTemplate(config.PunchTemplate, sheetWidth, sheetHeight, config.PagesPerSheet, config.Binding)
 	}
 
-	// Step 5: Add section marking to the booklet
-	err = addSectionMarking(config.OutputFile, config.Sections, config.PagesPerSheet)
+	// Step 7: Add section marking to the booklet; perfect binding has no signatures, only the stack height of the spine
+	switch {
+	case config.PerfectBinding:
+		reportStackHeight(preparedPages, config.Caliper, config.GSM)
+	case config.Marks == MarkSpine && template != nil && template.Folded:
+		// Drawn on the final fold with the stations
+	case config.Marks == MarkSpine:
+		_, err = addCollationMarks(config.OutputFile, totalSides, pagesPerSignature, config.PagesPerSheet, sheetWidth, sheetHeight, config.Binding)
+	default:
+		err = addSectionMarking(config.OutputFile, config.Sections, config.PagesPerSheet, config.Numerals)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to add section marking: %w", err)
 	}
 
+	// Step 8: Warn about marks and content in the printer's unprintable area
+	checkPrintableArea(config, sheetWidth, sheetHeight, totalSides, order, placements)
+
+	// Step 9: Generate the print-ready files for the duplex mode; template sheets are already whole sheet sides
+	printSides, printPerSignature, printPerSheet := totalSides, pagesPerSignature, config.PagesPerSheet
+	switch {
+	case template != nil:
+		printSides, printPerSignature, printPerSheet = 2*len(imposed), 2*sheetsPerSignature, 1
+	case config.PerfectBinding:
+		// Every cell is one page and the whole book is one run; 2-up sheets cut into two stacks
+		printSides, printPerSignature = preparedPages, 0
+	}
+	printOpts := PrintOptions{
+		Mode:        config.Duplex,
+		Scope:       config.DuplexScope,
+		Face:        config.Face,
+		BackOffsetX: config.BackOffsetX,
+		BackOffsetY: config.BackOffsetY,
+		BackScale:   config.BackScale,
+		Binding:     config.Binding,
+		CutStack:    config.CutStack || (config.PerfectBinding && config.PagesPerSheet == 2),
+		Repeat:      config.Repeat,
+		Perfect:     config.PerfectBinding,
+		Numerals:    config.Numerals,
+	}
+	printFiles := generatePrintPages(config.OutputFile, printSides, printPerSignature, printPerSheet, printOpts)
+	marked := markPrintFiles(printReadyDir, printFiles, sides)
+	if template != nil || config.PerfectBinding {
+		for i := range marked {
+			marked[i].Sides = nil
+		}
+	}
+	err = writePrintReadyMarkers(printReadyDir, markerLayout(config, &printOpts), marked)
+	if err != nil {
+		return fmt.Errorf("failed to record print-ready page markers: %w", err)
+	}
+	if config.DebugOverlay && template != nil {
+		fmt.Println("Warning: the debug overlay is only drawn for the -pages layouts, not for imposition templates")
+	} else if config.DebugOverlay && config.PerfectBinding {
+		fmt.Println("Warning: the debug overlay is only drawn for the -pages layouts, not for perfect binding")
+	} else if config.DebugOverlay {
+		slots := overlaySlots(printFiles, sides, pagesPerSignature, sheetWidth, sheetHeight, config.PagesPerSheet, config.Binding, pages)
+		err = addDebugOverlay(slots, config.PagesPerSheet)
+		if err != nil {
+			return fmt.Errorf("failed to add debug overlay: %w", err)
+		}
+	}
+
 	// Clean up temporary files
 	_ = removeTempFiles(tempFile, reversedFile)
 
 	return nil
 }
 
+// loadBookletImposition loads the imposition template of the config, nil for the -pages layout
+//
+// A template with 1, 2, 4 or 8 folios per sheet side also sets the pages per
+// sheet, so the stations and marks match its grid.
+func loadBookletImposition(config *BookletConfig) (*ImpositionTemplate, error) {
+	if config.Imposition == "" {
+		return nil, nil
+	}
+	configFile := config.ConfigFile
+	if configFile == "" {
+		configFile = defaultConfigFile
+	}
+	template, err := loadImposition(configFile, config.Imposition)
+	if err != nil {
+		return nil, err
+	}
+
+	folios := template.Columns * template.Rows / 2
+	if folios == 1 || folios == 2 || folios == 4 || folios == 8 {
+		config.PagesPerSheet = folios
+	}
+	fmt.Printf("Using imposition template %s: %dx%d grid, %d pages per sheet\n",
+		template.Name, template.Columns, template.Rows, template.SheetPages())
+	return &template, nil
+}
+
+// bookletPageOrder returns the input page on every prepared page, 0 for a blank
+func bookletPageOrder(config *BookletConfig, totalPages int) ([]int, PaddingPlan, []Bookmark, error) {
+	padding, err := parsePadding(config.Padding)
+	if err != nil {
+		return nil, PaddingPlan{}, nil, err
+	}
+
+	var outline []Bookmark
+	if config.ChaptersRecto {
+		outline, err = readOutline(config.InputFile)
+		if err != nil {
+			return nil, PaddingPlan{}, nil, fmt.Errorf("failed to read outline: %w", err)
+		}
+	}
+
+	pagesPerSignature := signaturePageCount(config.Sections, config.PagesPerSheet)
+	if config.PerfectBinding {
+		// Glued books are padded to whole leaves, not signatures
+		pagesPerSignature = 2
+	}
+	order, plan, err := coverPageOrder(totalPages, config.AddBlank, pagesPerSignature, config.Cover, padding, chapterStarts(outline))
+	return order, plan, outline, err
+}
+
 // prepareBookletPages prepares the PDF with blank pages for proper booklet formatting
 func prepareBookletPages(inputFile, outputFile string, addBlank, nsections, pagesPerSheet int) error {
 	if addBlank == 0 {
@@ -72,6 +377,16 @@ func prepareBookletPages(inputFile, outputFile string, addBlank, nsections, page
 		return nil
 	}
 
+	pagesPerSignature := signaturePageCount(nsections, pagesPerSheet)
+
+	// In a real implementation, this would process the PDF
+	fmt.Printf("Preparing booklet pages: %s -> %s, pagesPerSignature: %d\n", inputFile, outputFile, pagesPerSignature)
+
+	return nil
+}
+
+// signaturePageCount returns the number of pages in one signature
+func signaturePageCount(nsections, pagesPerSheet int) int {
 	// Determine multiplier based on pages per sheet
 	var folioMultiplier int
 	switch pagesPerSheet {
@@ -83,12 +398,21 @@ func prepareBookletPages(inputFile, outputFile string, addBlank, nsections, page
 		folioMultiplier = 2
 	}
 
-	pagesPerSignature := nsections * folioMultiplier
+	return nsections * folioMultiplier
+}
 
-	// In a real implementation, this would process the PDF
-	fmt.Printf("Preparing booklet pages: %s -> %s, pagesPerSignature: %d\n", inputFile, outputFile, pagesPerSignature)
+// paddedPageCount returns the page count after blank pages are added by the bookit.sh rule
+func paddedPageCount(totalPages, addBlank, pagesPerSignature int) int {
+	plan, _ := planPadding(1, totalPages, addBlank, pagesPerSignature, defaultPadding, nil)
+	return totalPages + plan.Blanks()
+}
 
-	return nil
+// frontBlankCount returns the number of blank pages inserted before the first input page
+func frontBlankCount(addBlank int) int {
+	if addBlank == 0 {
+		return 0
+	}
+	return 2
 }
 
 // handleReadingDirection reverses pages for RTL reading direction
@@ -98,36 +422,46 @@ func handleReadingDirection(inputFile, outputFile string) error {
 	return nil
 }
 
-// createBooklet creates the actual booklet layout
-func createBooklet(inputFile, outputFile string, pagesPerSheet int) error {
+// createBooklet creates the actual booklet layout with the pdfcpu booklet options
+func createBooklet(inputFile, outputFile string, pagesPerSheet int, options string) error {
 	fmt.Printf("Creating booklet layout: %s -> %s, pagesPerSheet: %d\n", inputFile, outputFile, pagesPerSheet)
+	fmt.Printf("  pdfcpu booklet -- %q\n", options)
 	// In a real implementation, this would create the booklet layout
 	return nil
 }
 
-// addStations adds sewing points/stations to the PDF
-func addStations(pdfFile string, pagesPerSheet int) error {
-	// Define station configurations based on the x-up format
-	var stationsConfig []float64
+// stationPercentages returns the station positions along the spine for the x-up format
+func stationPercentages(pagesPerSheet int) []float64 {
 	switch pagesPerSheet {
 	case 1: // A5 (1-up) - 8 points
-		stationsConfig = []float64{7.0, 19.3, 31.6, 43.9, 56.1, 68.4, 80.7, 93.0}
+		return []float64{7.0, 19.3, 31.6, 43.9, 56.1, 68.4, 80.7, 93.0}
 	case 2: // A6 (2-up) - 6 points
-		stationsConfig = []float64{8.0, 24.8, 41.6, 58.4, 75.2, 92.0}
+		return []float64{8.0, 24.8, 41.6, 58.4, 75.2, 92.0}
 	case 4, 8: // A7 (4-up) or 8-up - 4 points
-		stationsConfig = []float64{10.0, 36.6, 63.3, 90.0}
+		return []float64{10.0, 36.6, 63.3, 90.0}
 	default: // Default to 4 points
-		stationsConfig = []float64{10.0, 36.6, 63.3, 90.0}
+		return []float64{10.0, 36.6, 63.3, 90.0}
 	}
+}
+
+// addStations adds sewing points/stations to the PDF
+func addStations(pdfFile string, pagesPerSheet int, binding string) error {
+	// Define station configurations based on the x-up format
+	stationsConfig := stationPercentages(pagesPerSheet)
 
-	fmt.Printf("Adding stations to %s, configuration: %v\n", pdfFile, stationsConfig)
+	// Top-bound stations run along the horizontal fold, measured from its left end
+	fold := "spine"
+	if !foldIsVertical(binding) {
+		fold = "top"
+	}
+	fmt.Printf("Adding stations to %s along the %s fold, configuration: %v\n", pdfFile, fold, stationsConfig)
 
 	// In a real implementation, this would add the stations to the PDF
 	return nil
 }
 
 // addSectionMarking adds section marking to the PDF
-func addSectionMarking(pdfFile string, nsections, pagesPerSheet int) error {
+func addSectionMarking(pdfFile string, nsections, pagesPerSheet int, numerals string) error {
 	fmt.Printf("Adding section marking to %s, sections: %d, pagesPerSheet: %d\n", pdfFile, nsections, pagesPerSheet)
 
 	// Calculate pages per section based on nsections
@@ -156,7 +490,7 @@ func addSectionMarking(pdfFile string, nsections, pagesPerSheet int) error {
 			// Only apply to even pages
 			if page%2 == 0 {
 				// Format the folio number with leading zero
-				folioNumberStr := fmt.Sprintf("%02d", folioNum)
+				folioNumberStr := localizeDigits(fmt.Sprintf("%02d", folioNum), numerals)
 				fmt.Printf("  Adding folio marking %s to page %d at position %d\n", folioNumberStr, page, sectionPosition)
 				folioNum++
 			}
@@ -175,4 +509,4 @@ func removeTempFiles(files ...string) error {
 		}
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -140,4 +140,25 @@ func TestPageCalculations(t *testing.T) {
 				tc.pagesPerSheet, tc.expectedFolioMult, folioMultiplier)
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestPaddedPageCount(t *testing.T) {
+	testCases := []struct {
+		totalPages, addBlank, pagesPerSignature int
+		expected                                int
+	}{
+		{100, 0, 16, 100},
+		{100, 1, 16, 112}, // 2 front + 2 end = 104, filled to 112
+		{10, 1, 4, 16},    // 12 + 2 (already aligned, bookit.sh still adds 2)
+		{11, 1, 4, 16},    // 13 + 3
+		{13, 1, 0, 18},    // 15 + 3, no signature filling
+	}
+
+	for _, tc := range testCases {
+		total := paddedPageCount(tc.totalPages, tc.addBlank, tc.pagesPerSignature)
+		if total != tc.expected {
+			t.Errorf("For %d pages, addBlank=%d, pagesPerSignature=%d, expected %d, got %d",
+				tc.totalPages, tc.addBlank, tc.pagesPerSignature, tc.expected, total)
+		}
+	}
+}
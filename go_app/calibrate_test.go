@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalibrationMarks(t *testing.T) {
+	crosshair := calibrationMarks(a4Width, a4Height, PatternCrosshair)
+	if len(crosshair) != 5 {
+		t.Errorf("Expected 5 crosshairs, got %d", len(crosshair))
+	}
+	if crosshair[0].X != a4Width/2 || crosshair[0].Y != a4Height/2 {
+		t.Errorf("Expected the first crosshair at the sheet center, got %+v", crosshair[0])
+	}
+
+	grid := calibrationMarks(a4Width, a4Height, PatternGrid)
+	vlines, hlines := 0, 0
+	for _, mark := range grid {
+		switch mark.Kind {
+		case "vline":
+			vlines++
+			if mark.X < 0 || mark.X > a4Width {
+				t.Errorf("Vertical line outside the sheet: %+v", mark)
+			}
+		case "hline":
+			hlines++
+		}
+	}
+	// A4 is 210x297 mm, lines every 10 mm from the center
+	if vlines != 21 || hlines != 29 {
+		t.Errorf("Expected 21 vertical and 29 horizontal lines, got %d and %d", vlines, hlines)
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	x, y, err := parseOffset("1.5, -0.25")
+	if err != nil || x != 1.5 || y != -0.25 {
+		t.Errorf("Expected offset 1.5,-0.25, got %g,%g (%v)", x, y, err)
+	}
+
+	x, y, err = parseOffset("")
+	if err != nil || x != 0 || y != 0 {
+		t.Errorf("Expected empty offset to be 0,0, got %g,%g (%v)", x, y, err)
+	}
+
+	for _, value := range []string{"1.5", "a,b", "1,2,3"} {
+		if _, _, err := parseOffset(value); err == nil {
+			t.Errorf("Expected error for offset %q, got nil", value)
+		}
+	}
+}
+
+func TestBackCorrection(t *testing.T) {
+	dx, dy := backCorrection(25.4, 0, 0)
+	if math.Abs(dx-72) > 0.0001 || dy != 0 {
+		t.Errorf("Expected 25.4 mm to be 72 points, got %g,%g", dx, dy)
+	}
+
+	dx, dy = backCorrection(1.5, 1, 180)
+	if dx >= 0 || dy >= 0 {
+		t.Errorf("Expected the correction to turn with a 180 degree back, got %g,%g", dx, dy)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import "fmt"
+
+// Bookmark is one entry of the PDF outline
+type Bookmark struct {
+	Title string
+	Page  int // Destination page
+	Level int // 1 for top-level entries
+}
+
+// readOutline reads the bookmarks of the input PDF
+func readOutline(inputFile string) ([]Bookmark, error) {
+	// In a real implementation, this would read the outline with pdfcpu
+	return []Bookmark{
+		{Title: "Contents", Page: 3, Level: 1},
+		{Title: "Chapter 1", Page: 5, Level: 1},
+		{Title: "Chapter 2", Page: 18, Level: 1},
+		{Title: "Section 2.1", Page: 24, Level: 2},
+		{Title: "Chapter 3", Page: 37, Level: 1},
+		{Title: "Chapter 4", Page: 52, Level: 1},
+		{Title: "Chapter 5", Page: 71, Level: 1},
+		{Title: "Chapter 6", Page: 86, Level: 1},
+	}, nil
+}
+
+// chapterStarts returns the pages of the top-level bookmarks in page order
+func chapterStarts(bookmarks []Bookmark) []int {
+	var pages []int
+	last := 0
+	for _, bookmark := range bookmarks {
+		if bookmark.Level != 1 || bookmark.Page <= last {
+			continue
+		}
+		pages = append(pages, bookmark.Page)
+		last = bookmark.Page
+	}
+	return pages
+}
+
+// chapterBreaks returns the chapter pages that need a blank before them to start on a recto
+//
+// Positions count from the first prepared page after front blanks. Rectos are
+// odd positions in reading order, which puts them on the left in RTL books.
+//
+// End blanks inserted before page before fill the book to an even count, so
+// they absorb any blank added in front of a later chapter. Those chapters are
+// placed from the end of the book instead, with the blank at the end of the
+// chapter: before the next chapter, or before page last+1, after the last page.
+// When before is the last page, it stays last and isn't a chapter.
+func chapterBreaks(chapters []int, first, last, front, before int) []int {
+	var breaks, late []int
+	for _, page := range chapters {
+		if page <= first || page > last || (page == last && before == last) {
+			continue
+		}
+		if before > 0 && page >= before {
+			late = append(late, page)
+			continue
+		}
+		position := front + page - first + 1 + len(breaks)
+		if !isRecto(position) {
+			breaks = append(breaks, page)
+		}
+	}
+
+	var endBreaks []int
+	for i := len(late) - 1; i >= 0; i-- {
+		// A chapter is on a recto when an even number of pages follow its first one
+		remaining := last - late[i] + 1 + len(endBreaks)
+		if remaining%2 == 1 {
+			next := last + 1
+			if i+1 < len(late) {
+				next = late[i+1]
+			}
+			endBreaks = append([]int{next}, endBreaks...)
+		}
+	}
+	return append(breaks, endBreaks...)
+}
+
+// remapOutline moves the bookmarks to their pages in the prepared PDF
+//
+// Bookmarks on pages that are not imposed, like separate covers, are dropped.
+func remapOutline(bookmarks []Bookmark, order []int) []Bookmark {
+	positions := make(map[int]int, len(order))
+	for i, page := range order {
+		if page > 0 {
+			positions[page] = i + 1
+		}
+	}
+
+	var remapped []Bookmark
+	for _, bookmark := range bookmarks {
+		position, ok := positions[bookmark.Page]
+		if !ok {
+			continue
+		}
+		bookmark.Page = position
+		remapped = append(remapped, bookmark)
+	}
+	return remapped
+}
+
+// updateOutline writes the remapped bookmarks to the prepared PDF
+func updateOutline(pdfFile string, bookmarks []Bookmark) error {
+	fmt.Printf("Updating outline of %s, %d bookmarks\n", pdfFile, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if bookmark.Level == 1 {
+			fmt.Printf("  %s: page %d\n", bookmark.Title, bookmark.Page)
+		}
+	}
+
+	// In a real implementation, this would replace the outline with pdfcpu
+	return nil
+}
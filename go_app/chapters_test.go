@@ -0,0 +1,110 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChapterStarts(t *testing.T) {
+	bookmarks := []Bookmark{
+		{Title: "One", Page: 3, Level: 1},
+		{Title: "One.1", Page: 4, Level: 2},
+		{Title: "Two", Page: 8, Level: 1},
+		{Title: "Two again", Page: 8, Level: 1},
+	}
+
+	expected := []int{3, 8}
+	if starts := chapterStarts(bookmarks); !reflect.DeepEqual(starts, expected) {
+		t.Errorf("Expected %v, got %v", expected, starts)
+	}
+}
+
+func TestChapterBreaks(t *testing.T) {
+	tests := []struct {
+		name     string
+		chapters []int
+		front    int
+		before   int
+		expected []int
+	}{
+		{"already on rectos", []int{3, 5}, 0, 0, nil},
+		{"one verso", []int{4}, 0, 0, []int{4}},
+		{"blank shifts later chapters", []int{4, 6, 9}, 0, 0, []int{4, 9}},
+		{"front blanks keep parity", []int{3}, 2, 0, nil},
+		{"odd front blank", []int{3}, 1, 0, []int{3}},
+		{"first page is never moved", []int{1}, 1, 0, nil},
+		{"chapters after before count from the end", []int{4, 12, 15}, 0, 10, []int{4, 15}},
+		{"blank after the last page", []int{4, 20}, 0, 10, []int{4, 21}},
+		{"last page kept last", []int{20}, 0, 20, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			breaks := chapterBreaks(test.chapters, 1, 20, test.front, test.before)
+			if !reflect.DeepEqual(breaks, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, breaks)
+			}
+		})
+	}
+}
+
+func TestChaptersStartOnRecto(t *testing.T) {
+	chapters := []int{4, 6, 11}
+	order, plan, err := coverPageOrder(12, 1, 8, CoverNone, defaultPadding, chapters)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(order)%8 != 0 {
+		t.Errorf("Expected the book padded to the signature size, got %d pages", len(order))
+	}
+	if plan.Chapters != 2 {
+		t.Errorf("Expected 2 chapter blanks, got %d", plan.Chapters)
+	}
+	for _, bookmark := range remapOutline([]Bookmark{{Page: 4, Level: 1}, {Page: 6, Level: 1}, {Page: 11, Level: 1}}, order) {
+		if !isRecto(bookmark.Page) {
+			t.Errorf("Expected chapter on a recto, got page %d", bookmark.Page)
+		}
+	}
+}
+
+func TestChaptersStartOnRectoWithPaddingBefore(t *testing.T) {
+	chapters := []int{5, 18, 37}
+	for _, spec := range []string{"before=20", "before=30", "front=1,before=10", "before-last"} {
+		t.Run(spec, func(t *testing.T) {
+			policy, err := parsePadding(spec)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			order, _, err := coverPageOrder(99, 1, 16, CoverNone, policy, chapters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(order)%16 != 0 {
+				t.Errorf("Expected the book padded to the signature size, got %d pages", len(order))
+			}
+			if order[len(order)-1] == 0 && policy.BeforeLast {
+				t.Error("Expected the last page kept last, got a blank")
+			}
+			for _, bookmark := range remapOutline([]Bookmark{{Page: 5, Level: 1}, {Page: 18, Level: 1}, {Page: 37, Level: 1}}, order) {
+				if !isRecto(bookmark.Page) {
+					t.Errorf("Expected chapter on a recto, got page %d", bookmark.Page)
+				}
+			}
+		})
+	}
+}
+
+func TestRemapOutline(t *testing.T) {
+	bookmarks := []Bookmark{
+		{Title: "Cover", Page: 1, Level: 1},
+		{Title: "Chapter", Page: 3, Level: 1},
+	}
+	order := []int{0, 0, 2, 0, 3, 4}
+
+	remapped := remapOutline(bookmarks, order)
+	if len(remapped) != 1 || remapped[0].Title != "Chapter" || remapped[0].Page != 5 {
+		t.Errorf("Expected only the chapter on page 5, got %+v", remapped)
+	}
+}
@@ -10,6 +10,19 @@ type CLI struct{}
 
 // Run executes the CLI application
 func (cli *CLI) Run(args []string) error {
+	if len(args) > 1 {
+		switch args[1] {
+		case "calibrate":
+			return cli.runCalibrate(args[2:])
+		case "cover":
+			return cli.runCover(args[2:])
+		case "verify":
+			return cli.runVerify(args[2:])
+		case "number":
+			return cli.runNumber(args[2:])
+		}
+	}
+
 	var (
 		inputFile        string
 		outputFile       string = "booklet.pdf"
@@ -17,6 +30,55 @@ func (cli *CLI) Run(args []string) error {
 		readingDirection string = "RTL"
 		sections         int    = 8
 		addBlank         int    = 1
+		scale            string = ScaleFit
+		align            string = DefaultAlign
+		rotate           string = RotateOff
+		duplexScope      string = DuplexPerSignature
+		backOffset       string
+		backScale        float64
+
+		// Printer-dependent settings stay empty unless set, so a printer profile can fill them
+		duplex     string
+		face       string
+		sheet      string
+		margin     float64
+		printer    string
+		configFile string = defaultConfigFile
+
+		punchTemplate string
+		noStations    bool
+		marks         string = MarkFolio
+
+		signatureLabels string = LabelsOff
+		signatureLeaf   bool
+		bookTitle       string
+
+		cover   string = CoverNone
+		padding string
+
+		chaptersRecto bool
+		previewFile   string
+		debugOverlay  bool
+
+		numbering  string
+		numberSkip string
+
+		runningHeads bool
+		headsFile    string
+
+		numerals string = NumeralsWestern
+		fontFile string = defaultFontFile
+
+		binding    string
+		imposition string
+		cutStack   bool
+		repeat     bool
+
+		perfect bool
+		gutter  float64 = defaultGutter
+		grind   float64 = defaultGrind
+		caliper float64
+		gsm     float64
 	)
 
 	cliFlags := flag.NewFlagSet("booklet-maker", flag.ExitOnError)
@@ -26,12 +88,50 @@ func (cli *CLI) Run(args []string) error {
 	cliFlags.StringVar(&outputFile, "o", "booklet.pdf", "Output PDF file (shorthand)")
 	cliFlags.IntVar(&pagesPerSheet, "pages", 1, "Pages per sheet (1, 2, 4, or 8)")
 	cliFlags.IntVar(&pagesPerSheet, "p", 1, "Pages per sheet (shorthand)")
-	cliFlags.StringVar(&readingDirection, "direction", "RTL", "Reading direction (RTL or LTR)")
+	cliFlags.StringVar(&readingDirection, "direction", "RTL", "Reading direction (RTL, LTR, or auto)")
 	cliFlags.StringVar(&readingDirection, "d", "RTL", "Reading direction (shorthand)")
 	cliFlags.IntVar(&sections, "sections", 8, "Number of sections")
 	cliFlags.IntVar(&sections, "s", 8, "Number of sections (shorthand)")
 	cliFlags.IntVar(&addBlank, "blank", 1, "Add blank pages (0 or 1)")
 	cliFlags.IntVar(&addBlank, "b", 1, "Add blank pages (shorthand)")
+	cliFlags.StringVar(&scale, "scale", ScaleFit, "Scaling policy (fit, fill, actual, first, largest)")
+	cliFlags.StringVar(&align, "align", DefaultAlign, "Page alignment in its slot (c, tl, tc, tr, l, r, bl, bc, br)")
+	cliFlags.StringVar(&rotate, "rotate", RotateOff, "Auto-rotate landscape pages (off, cw, ccw)")
+	cliFlags.StringVar(&duplex, "duplex", "", "Print mode (manual, long, short) (default manual)")
+	cliFlags.StringVar(&duplexScope, "duplex-scope", DuplexPerSignature, "Interleaved duplex file per signature or book")
+	cliFlags.StringVar(&face, "face", "", "Output tray face for manual duplex (up or down) (default up)")
+	cliFlags.StringVar(&backOffset, "back-offset", "", "Back side correction X,Y in mm")
+	cliFlags.Float64Var(&backScale, "back-scale", 0, "Back side scale correction (default 1)")
+	cliFlags.StringVar(&sheet, "sheet", "", "Sheet size (A3, A4, A5, Letter, Legal) (default A4)")
+	cliFlags.Float64Var(&margin, "margin", 0, "Unprintable margin of the printer in mm")
+	cliFlags.StringVar(&printer, "printer", "", "Printer profile from the configuration file")
+	cliFlags.StringVar(&configFile, "config", defaultConfigFile, "Configuration file with printer profiles")
+	cliFlags.StringVar(&punchTemplate, "punch-template", "", "Write a sewing punch template PDF")
+	cliFlags.BoolVar(&noStations, "no-stations", false, "Don't stamp station dots on the sheets")
+	cliFlags.StringVar(&marks, "marks", MarkFolio, "Section marking (folio or spine)")
+	cliFlags.StringVar(&signatureLabels, "signature-labels", LabelsOff, "Printer's signature labels (off, letters, numbers)")
+	cliFlags.BoolVar(&signatureLeaf, "signature-leaf", false, "Also label the second leaf of each signature (A2)")
+	cliFlags.StringVar(&bookTitle, "title", "", "Short book title for the signature labels and running heads")
+	cliFlags.StringVar(&cover, "cover", CoverNone, "Cover pages handling (none, self, separate)")
+	cliFlags.BoolVar(&chaptersRecto, "chapters-recto", false, "Start every top-level bookmarked chapter on a recto")
+	cliFlags.StringVar(&previewFile, "preview", "", "Write a fold preview PDF of the spreads the reader will see")
+	cliFlags.BoolVar(&debugOverlay, "debug-overlay", false, "Draw slot boundaries and page labels on the print-ready sheets")
+	cliFlags.StringVar(&numbering, "numbering", "", "Page numbering rules, e.g. 1-4=roman;5-=arabic,start=1")
+	cliFlags.StringVar(&numberSkip, "number-skip", "", "Pages that don't show their number (blank, chapter)")
+	cliFlags.BoolVar(&runningHeads, "running-heads", false, "Stamp the title on versos and the chapter on rectos")
+	cliFlags.StringVar(&headsFile, "heads-file", "", "Chapter titles for the running heads, one \"PAGE Title\" per line")
+	cliFlags.StringVar(&numerals, "numerals", NumeralsWestern, "Numeral system of stamped numbers (western, arabic-indic, persian, devanagari, roman)")
+	cliFlags.StringVar(&fontFile, "font", defaultFontFile, "Font the marks are stamped with")
+	cliFlags.StringVar(&binding, "binding", "", "Binding edge (left, right, top) (default: the reading direction's side)")
+	cliFlags.StringVar(&imposition, "imposition", "", "Imposition template name, JSON or YAML file (default: the -pages layout)")
+	cliFlags.BoolVar(&cutStack, "cut-stack", false, "Lay out 2, 4, or 8-up sheets to cut into stacks in reading order")
+	cliFlags.BoolVar(&repeat, "repeat", false, "Repeat every booklet side in all 2, 4, or 8-up cells, one copy per cell")
+	cliFlags.BoolVar(&perfect, "perfect", false, "Perfect binding: glue single leaves in reading order, 1 or 2 pages per sheet")
+	cliFlags.Float64Var(&gutter, "gutter", defaultGutter, "Perfect binding shift away from the glued edge in mm")
+	cliFlags.Float64Var(&grind, "grind", defaultGrind, "Perfect binding spine roughening allowance in mm")
+	cliFlags.Float64Var(&caliper, "caliper", 0, "Paper thickness per leaf in mm, for the stack height")
+	cliFlags.Float64Var(&gsm, "gsm", 0, "Paper weight in g/m², used when the caliper is unknown (default 80)")
+	cliFlags.StringVar(&padding, "padding", "", "Padding policy, e.g. front=0,aligned,before-last (default bookit.sh rule)")
 
 	err := cliFlags.Parse(args[1:])
 	if err != nil {
@@ -50,8 +150,8 @@ func (cli *CLI) Run(args []string) error {
 	}
 
 	// Validate reading direction
-	if readingDirection != "RTL" && readingDirection != "LTR" {
-		return fmt.Errorf("reading direction must be RTL or LTR, got %s", readingDirection)
+	if readingDirection != "RTL" && readingDirection != "LTR" && readingDirection != DirectionAuto {
+		return fmt.Errorf("reading direction must be RTL, LTR, or auto, got %s", readingDirection)
 	}
 
 	// Validate add blank
@@ -59,6 +159,131 @@ func (cli *CLI) Run(args []string) error {
 		return fmt.Errorf("add blank must be 0 or 1, got %d", addBlank)
 	}
 
+	// Validate scaling policy
+	if !isValidOption(scale, validScaleModes) {
+		return fmt.Errorf("scale must be fit, fill, actual, first, or largest, got %s", scale)
+	}
+
+	// Validate alignment
+	if !isValidOption(align, validAlignments) {
+		return fmt.Errorf("align must be one of c, tl, tc, tr, l, r, bl, bc, br, got %s", align)
+	}
+
+	// Validate auto-rotation
+	if !isValidOption(rotate, validRotateModes) {
+		return fmt.Errorf("rotate must be off, cw, or ccw, got %s", rotate)
+	}
+
+	// Validate duplex options
+	if duplex != "" && !isValidOption(duplex, validDuplexModes) {
+		return fmt.Errorf("duplex must be manual, long, or short, got %s", duplex)
+	}
+	if !isValidOption(duplexScope, validDuplexScopes) {
+		return fmt.Errorf("duplex scope must be signature or book, got %s", duplexScope)
+	}
+	if face != "" && !isValidOption(face, validFaces) {
+		return fmt.Errorf("face must be up or down, got %s", face)
+	}
+
+	// Validate back-side correction; an explicit 0,0 still overrides the printer profile
+	backOffsetX, backOffsetY, err := parseOffset(backOffset)
+	if err != nil {
+		return err
+	}
+	backOffsetSet, backScaleSet, marginSet, directionSet := false, false, false, false
+	cliFlags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "back-offset":
+			backOffsetSet = true
+		case "back-scale":
+			backScaleSet = true
+		case "margin":
+			marginSet = true
+		case "direction", "d":
+			directionSet = true
+		}
+	})
+	if backScale < 0 {
+		return fmt.Errorf("back scale must be positive, got %g", backScale)
+	}
+
+	// Validate sheet size and margin
+	if sheet != "" {
+		if _, _, err := sheetSize(sheet); err != nil {
+			return err
+		}
+	}
+	if margin < 0 {
+		return fmt.Errorf("margin must not be negative, got %g", margin)
+	}
+
+	// Validate section marking
+	if !isValidOption(marks, validMarkModes) {
+		return fmt.Errorf("marks must be folio or spine, got %s", marks)
+	}
+
+	// Validate signature labels
+	if !isValidOption(signatureLabels, validLabelStyles) {
+		return fmt.Errorf("signature labels must be off, letters, or numbers, got %s", signatureLabels)
+	}
+
+	// Validate cover mode
+	if !isValidOption(cover, validCoverModes) {
+		return fmt.Errorf("cover must be none, self, or separate, got %s", cover)
+	}
+
+	// Validate padding policy
+	if _, err := parsePadding(padding); err != nil {
+		return err
+	}
+
+	// Validate numeral system
+	if !isValidOption(numerals, validNumeralSystems) {
+		return fmt.Errorf("numerals must be western, arabic-indic, persian, devanagari, or roman, got %s", numerals)
+	}
+
+	// Validate binding edge
+	if binding != "" && !isValidOption(binding, validBindings) {
+		return fmt.Errorf("binding must be left, right, or top, got %s", binding)
+	}
+
+	// Validate cut-and-stack
+	if cutStack {
+		if err := validateCutStack(pagesPerSheet, imposition); err != nil {
+			return err
+		}
+	}
+
+	// Validate step-and-repeat
+	if repeat {
+		if err := validateRepeat(pagesPerSheet, imposition, cutStack); err != nil {
+			return err
+		}
+	}
+
+	// Validate perfect binding
+	if perfect {
+		if err := validatePerfectBinding(pagesPerSheet, binding, imposition, cutStack, repeat); err != nil {
+			return err
+		}
+	}
+	if gutter < 0 || grind < 0 {
+		return fmt.Errorf("gutter and grind must not be negative, got %g and %g", gutter, grind)
+	}
+	if caliper < 0 || gsm < 0 {
+		return fmt.Errorf("caliper and gsm must not be negative, got %g and %g", caliper, gsm)
+	}
+
+	// Validate page numbering
+	if numbering != "" {
+		if _, err := parseNumbering(numbering); err != nil {
+			return err
+		}
+	}
+	if _, err := parseNumberSkip(numberSkip); err != nil {
+		return err
+	}
+
 	config := &BookletConfig{
 		InputFile:        inputFile,
 		OutputFile:       outputFile,
@@ -66,6 +291,49 @@ func (cli *CLI) Run(args []string) error {
 		ReadingDirection: readingDirection,
 		Sections:         sections,
 		AddBlank:         addBlank,
+		Scale:            scale,
+		Align:            align,
+		Rotate:           rotate,
+		Duplex:           duplex,
+		DuplexScope:      duplexScope,
+		Face:             face,
+		BackOffsetX:      backOffsetX,
+		BackOffsetY:      backOffsetY,
+		BackOffsetSet:    backOffsetSet,
+		DirectionSet:     directionSet,
+		BackScale:        backScale,
+		BackScaleSet:     backScaleSet,
+		Sheet:            sheet,
+		Margin:           margin,
+		MarginSet:        marginSet,
+		Printer:          printer,
+		ConfigFile:       configFile,
+		PunchTemplate:    punchTemplate,
+		NoStations:       noStations,
+		Marks:            marks,
+		SignatureLabels:  signatureLabels,
+		SignatureLeaf:    signatureLeaf,
+		BookTitle:        bookTitle,
+		Cover:            cover,
+		Padding:          padding,
+		ChaptersRecto:    chaptersRecto,
+		PreviewFile:      previewFile,
+		DebugOverlay:     debugOverlay,
+		Numbering:        numbering,
+		NumberSkip:       numberSkip,
+		RunningHeads:     runningHeads || headsFile != "",
+		HeadsFile:        headsFile,
+		Numerals:         numerals,
+		FontFile:         fontFile,
+		Binding:          binding,
+		Imposition:       imposition,
+		CutStack:         cutStack,
+		Repeat:           repeat,
+		PerfectBinding:   perfect,
+		Gutter:           gutter,
+		Grind:            grind,
+		Caliper:          caliper,
+		GSM:              gsm,
 	}
 
 	return ProcessBooklet(config)
@@ -78,12 +346,323 @@ func (cli *CLI) printUsage() {
 	fmt.Println("  -input, -i        Input PDF file (required)")
 	fmt.Println("  -output, -o       Output PDF file (default: booklet.pdf)")
 	fmt.Println("  -pages, -p        Pages per sheet (1, 2, 4, or 8) (default: 1)")
-	fmt.Println("  -direction, -d    Reading direction (RTL or LTR) (default: RTL)")
+	fmt.Println("  -direction, -d    Reading direction (RTL, LTR, or auto) (default: RTL)")
 	fmt.Println("  -sections, -s     Number of sections (default: 8)")
 	fmt.Println("  -blank, -b        Add blank pages (0 or 1) (default: 1)")
+	fmt.Println("  -scale            Scaling policy: fit, fill, actual, first, largest (default: fit)")
+	fmt.Println("  -align            Page alignment in its slot: c, tl, tc, tr, l, r, bl, bc, br (default: c)")
+	fmt.Println("  -rotate           Auto-rotate landscape pages: off, cw, ccw (default: off)")
+	fmt.Println("  -duplex           Print mode: manual, long, short (default: manual)")
+	fmt.Println("  -duplex-scope     Interleaved duplex file per signature or book (default: signature)")
+	fmt.Println("  -face             Output tray face for manual duplex: up, down (default: up)")
+	fmt.Println("  -back-offset      Back side correction X,Y in mm (e.g. 1.5,0)")
+	fmt.Println("  -back-scale       Back side scale correction (default: 1)")
+	fmt.Println("  -sheet            Sheet size: A3, A4, A5, Letter, Legal (default: A4)")
+	fmt.Println("  -margin           Unprintable margin of the printer in mm (default: 0)")
+	fmt.Println("  -printer          Printer profile from the configuration file")
+	fmt.Println("  -config           Configuration file (default: booklet-maker.json)")
+	fmt.Println("  -punch-template   Write a sewing punch template PDF")
+	fmt.Println("  -no-stations      Don't stamp station dots on the sheets")
+	fmt.Println("  -marks            Section marking: folio, spine (default: folio)")
+	fmt.Println("  -signature-labels Printer's signature labels: off, letters, numbers (default: off)")
+	fmt.Println("  -signature-leaf   Also label the second leaf of each signature (A2)")
+	fmt.Println("  -title            Short book title for the signature labels and running heads")
+	fmt.Println("  -cover            Cover pages handling: none, self, separate (default: none)")
+	fmt.Println("  -padding          Padding policy, e.g. front=0,aligned,before-last (default: bookit.sh rule)")
+	fmt.Println("  -chapters-recto   Start every top-level bookmarked chapter on a recto")
+	fmt.Println("  -preview          Write a fold preview PDF of the spreads the reader will see")
+	fmt.Println("  -debug-overlay    Draw slot boundaries and page labels on the print-ready sheets")
+	fmt.Println("  -numbering        Page numbering rules, e.g. 1-4=roman;5-=arabic,start=1")
+	fmt.Println("  -number-skip      Pages that don't show their number: blank, chapter")
+	fmt.Println("  -running-heads    Stamp the title on versos and the chapter on rectos")
+	fmt.Println("  -heads-file       Chapter titles for the running heads, one \"PAGE Title\" per line")
+	fmt.Println("  -numerals         Numeral system: western, arabic-indic, persian, devanagari, roman (default: western)")
+	fmt.Println("  -font             Font the marks are stamped with (default: fonts/BigBlueTermPlusNerdFontMono-Regular.ttf)")
+	fmt.Println("  -binding          Binding edge: left, right, top (default: the reading direction's side)")
+	fmt.Println("  -imposition       Imposition template from the config file, a JSON or YAML file or built-in: booklet, 2-up, 4-up, 8-up, octavo")
+	fmt.Println("  -cut-stack        Lay out 2, 4, or 8-up sheets so each cut stack is a run of pages in reading order")
+	fmt.Println("  -repeat           Repeat every booklet side in all 2, 4, or 8-up cells, one copy of the booklet per cell")
+	fmt.Println("  -perfect          Perfect binding: glue single leaves in reading order, 1 or 2 pages per sheet")
+	fmt.Println("  -gutter           Perfect binding shift away from the glued edge in mm (default: 3)")
+	fmt.Println("  -grind            Perfect binding spine roughening allowance in mm (default: 3)")
+	fmt.Println("  -caliper          Paper thickness per leaf in mm, for the stack height")
+	fmt.Println("  -gsm              Paper weight in g/m², used when the caliper is unknown (default: 80)")
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  booklet-maker -input mybook.pdf")
 	fmt.Println("  booklet-maker -i mybook.pdf -o output.pdf -p 2 -d LTR")
 	fmt.Println("  booklet-maker -input book.pdf -pages 4 -sections 6 -blank 0")
-}
\ No newline at end of file
+	fmt.Println("  booklet-maker -input book.pdf -printer office")
+	fmt.Println("")
+	fmt.Println("Commands:")
+	fmt.Println("  booklet-maker calibrate [-pattern grid|crosshair] [-printer NAME -back-offset X,Y]")
+	fmt.Println("  booklet-maker cover -front front.pdf -page-count N (-caliper MM | -gsm G) [-back back.pdf] [-spine-text TEXT]")
+	fmt.Println("  booklet-maker number -i book.pdf -numbering RULES [-number-skip blank,chapter] [-o numbered.pdf]")
+	fmt.Println("  booklet-maker verify -i book.pdf [-booklet booklet.pdf | -print-ready print_ready] [booklet options]")
+}
+
+// runCalibrate writes a calibration sheet and saves a measured back-side offset
+func (cli *CLI) runCalibrate(args []string) error {
+	var (
+		outputFile string = "calibration.pdf"
+		pattern    string = PatternGrid
+		printer    string
+		backOffset string
+		backScale  float64
+		configFile string = defaultConfigFile
+	)
+
+	calibrateFlags := flag.NewFlagSet("calibrate", flag.ExitOnError)
+	calibrateFlags.StringVar(&outputFile, "output", "calibration.pdf", "Calibration sheet PDF file")
+	calibrateFlags.StringVar(&outputFile, "o", "calibration.pdf", "Calibration sheet PDF file (shorthand)")
+	calibrateFlags.StringVar(&pattern, "pattern", PatternGrid, "Calibration pattern (grid or crosshair)")
+	calibrateFlags.StringVar(&printer, "printer", "", "Printer profile to save the measured offset to")
+	calibrateFlags.StringVar(&backOffset, "back-offset", "", "Measured back side correction X,Y in mm")
+	calibrateFlags.Float64Var(&backScale, "back-scale", 0, "Measured back side scale correction")
+	calibrateFlags.StringVar(&configFile, "config", defaultConfigFile, "Configuration file with printer profiles")
+
+	err := calibrateFlags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if !isValidOption(pattern, validPatterns) {
+		return fmt.Errorf("pattern must be grid or crosshair, got %s", pattern)
+	}
+	if backScale < 0 {
+		return fmt.Errorf("back scale must be positive, got %g", backScale)
+	}
+
+	// Start from the saved profile so the sheet shows the current correction
+	saving := backOffset != "" || backScale > 0
+	var profile PrinterProfile
+	if printer != "" {
+		profile, err = loadPrinterProfile(configFile, printer)
+		if err != nil && !saving {
+			return err
+		}
+	}
+
+	// A measured offset or scale is saved on its own, keeping the other one
+	if saving {
+		if printer == "" {
+			return fmt.Errorf("printer name is required to save the back offset or scale")
+		}
+		if backOffset != "" {
+			profile.BackOffsetX, profile.BackOffsetY, err = parseOffset(backOffset)
+			if err != nil {
+				return err
+			}
+		}
+		if backScale > 0 {
+			profile.BackScale = backScale
+		}
+		if err := savePrinterProfile(configFile, printer, profile); err != nil {
+			return fmt.Errorf("failed to save printer profile: %w", err)
+		}
+		if backOffset != "" {
+			fmt.Printf("Saved back offset %.2f,%.2f mm to printer profile %q in %s\n",
+				profile.BackOffsetX, profile.BackOffsetY, printer, configFile)
+		}
+		if backScale > 0 {
+			fmt.Printf("Saved back scale %.4f to printer profile %q in %s\n", profile.BackScale, printer, configFile)
+		}
+		return nil
+	}
+
+	writeCalibrationSheet(outputFile, pattern, profile)
+	return nil
+}
+
+// runCover builds a wraparound cover with a computed spine width
+func (cli *CLI) runCover(args []string) error {
+	var (
+		outputFile    string = "cover.pdf"
+		spec                 = CoverSpec{Bleed: defaultBleed}
+		direction     string = "RTL"
+		pagesPerSheet int    = 1
+	)
+
+	coverFlags := flag.NewFlagSet("cover", flag.ExitOnError)
+	coverFlags.StringVar(&outputFile, "output", "cover.pdf", "Cover PDF file")
+	coverFlags.StringVar(&outputFile, "o", "cover.pdf", "Cover PDF file (shorthand)")
+	coverFlags.StringVar(&spec.FrontFile, "front", "", "Front cover image or PDF (required)")
+	coverFlags.StringVar(&spec.BackFile, "back", "", "Back cover image or PDF")
+	coverFlags.StringVar(&spec.SpineText, "spine-text", "", "Text printed on the spine")
+	coverFlags.IntVar(&spec.PageCount, "page-count", 0, "Final page count of the book after padding (required)")
+	coverFlags.Float64Var(&spec.Caliper, "caliper", 0, "Paper thickness per leaf in mm")
+	coverFlags.Float64Var(&spec.GSM, "gsm", 0, "Paper weight in g/m², used when the caliper is unknown")
+	coverFlags.IntVar(&pagesPerSheet, "pages", 1, "Pages per sheet of the interior (1, 2, 4, or 8)")
+	coverFlags.StringVar(&spec.InteriorSheet, "interior-sheet", DefaultSheet, "Sheet size the interior is printed on")
+	coverFlags.StringVar(&spec.Sheet, "sheet", "A3", "Sheet size the cover is printed on")
+	coverFlags.Float64Var(&spec.Bleed, "bleed", defaultBleed, "Bleed in mm")
+	coverFlags.StringVar(&direction, "direction", "RTL", "Reading direction (RTL or LTR)")
+
+	err := coverFlags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if spec.FrontFile == "" {
+		return fmt.Errorf("front cover file is required")
+	}
+	if pagesPerSheet != 1 && pagesPerSheet != 2 && pagesPerSheet != 4 && pagesPerSheet != 8 {
+		return fmt.Errorf("pages per sheet must be 1, 2, 4, or 8, got %d", pagesPerSheet)
+	}
+	if direction != "RTL" && direction != "LTR" {
+		return fmt.Errorf("reading direction must be RTL or LTR, got %s", direction)
+	}
+	if spec.Bleed < 0 {
+		return fmt.Errorf("bleed must not be negative, got %g", spec.Bleed)
+	}
+	spec.PagesPerSheet = pagesPerSheet
+	spec.Direction = direction
+
+	layout, err := computeCoverLayout(spec)
+	if err != nil {
+		return err
+	}
+	writeCover(outputFile, spec, layout)
+	return nil
+}
+
+// runVerify checks that the imposed output folds into a book that reads in order
+func (cli *CLI) runVerify(args []string) error {
+	var (
+		bookletFile string = "booklet.pdf"
+		printReady  string
+		config      = &BookletConfig{ConfigFile: defaultConfigFile, DuplexScope: DuplexPerSignature}
+	)
+
+	verifyFlags := flag.NewFlagSet("verify", flag.ExitOnError)
+	verifyFlags.StringVar(&config.InputFile, "input", "", "Source PDF file (required)")
+	verifyFlags.StringVar(&config.InputFile, "i", "", "Source PDF file (shorthand)")
+	verifyFlags.StringVar(&bookletFile, "booklet", "booklet.pdf", "Imposed booklet PDF file")
+	verifyFlags.StringVar(&printReady, "print-ready", "", "Verify the print-ready folder instead of the booklet")
+	verifyFlags.IntVar(&config.PagesPerSheet, "pages", 1, "Pages per sheet (1, 2, 4, or 8)")
+	verifyFlags.IntVar(&config.PagesPerSheet, "p", 1, "Pages per sheet (shorthand)")
+	verifyFlags.StringVar(&config.ReadingDirection, "direction", "RTL", "Reading direction (RTL, LTR, or auto)")
+	verifyFlags.StringVar(&config.ReadingDirection, "d", "RTL", "Reading direction (shorthand)")
+	verifyFlags.IntVar(&config.Sections, "sections", 8, "Number of sections")
+	verifyFlags.IntVar(&config.Sections, "s", 8, "Number of sections (shorthand)")
+	verifyFlags.IntVar(&config.AddBlank, "blank", 1, "Add blank pages (0 or 1)")
+	verifyFlags.IntVar(&config.AddBlank, "b", 1, "Add blank pages (shorthand)")
+	verifyFlags.StringVar(&config.Cover, "cover", CoverNone, "Cover pages handling (none, self, separate)")
+	verifyFlags.StringVar(&config.Padding, "padding", "", "Padding policy used for the booklet")
+	verifyFlags.BoolVar(&config.ChaptersRecto, "chapters-recto", false, "Chapters were started on a recto")
+	verifyFlags.StringVar(&config.Duplex, "duplex", "", "Print mode (manual, long, short) (default manual)")
+	verifyFlags.StringVar(&config.DuplexScope, "duplex-scope", DuplexPerSignature, "Interleaved duplex file per signature or book")
+	verifyFlags.StringVar(&config.Face, "face", "", "Output tray face for manual duplex (up or down) (default up)")
+	verifyFlags.StringVar(&config.Printer, "printer", "", "Printer profile from the configuration file")
+	verifyFlags.StringVar(&config.ConfigFile, "config", defaultConfigFile, "Configuration file with printer profiles")
+	verifyFlags.StringVar(&config.Binding, "binding", "", "Binding edge (left, right, top)")
+	verifyFlags.BoolVar(&config.CutStack, "cut-stack", false, "The print-ready sheets were laid out for cut-and-stack")
+	verifyFlags.BoolVar(&config.Repeat, "repeat", false, "The print-ready sheets repeat every side in all cells")
+	verifyFlags.StringVar(&config.Imposition, "imposition", "", "Imposition template the booklet was made with")
+	verifyFlags.BoolVar(&config.PerfectBinding, "perfect", false, "The booklet was laid out for perfect binding")
+
+	err := verifyFlags.Parse(args)
+	if err != nil {
+		return err
+	}
+	verifyFlags.Visit(func(f *flag.Flag) {
+		if f.Name == "direction" || f.Name == "d" {
+			config.DirectionSet = true
+		}
+	})
+
+	if config.InputFile == "" {
+		return fmt.Errorf("input file is required")
+	}
+	if config.PagesPerSheet != 1 && config.PagesPerSheet != 2 && config.PagesPerSheet != 4 && config.PagesPerSheet != 8 {
+		return fmt.Errorf("pages per sheet must be 1, 2, 4, or 8, got %d", config.PagesPerSheet)
+	}
+	if config.ReadingDirection != "RTL" && config.ReadingDirection != "LTR" && config.ReadingDirection != DirectionAuto {
+		return fmt.Errorf("reading direction must be RTL, LTR, or auto, got %s", config.ReadingDirection)
+	}
+	if !isValidOption(config.Cover, validCoverModes) {
+		return fmt.Errorf("cover must be none, self, or separate, got %s", config.Cover)
+	}
+	if config.Duplex != "" && !isValidOption(config.Duplex, validDuplexModes) {
+		return fmt.Errorf("duplex must be manual, long, or short, got %s", config.Duplex)
+	}
+	if config.Face != "" && !isValidOption(config.Face, validFaces) {
+		return fmt.Errorf("face must be up or down, got %s", config.Face)
+	}
+	if config.Binding != "" && !isValidOption(config.Binding, validBindings) {
+		return fmt.Errorf("binding must be left, right, or top, got %s", config.Binding)
+	}
+	if config.CutStack {
+		if err := validateCutStack(config.PagesPerSheet, ""); err != nil {
+			return err
+		}
+	}
+	if config.Repeat {
+		if err := validateRepeat(config.PagesPerSheet, "", config.CutStack); err != nil {
+			return err
+		}
+	}
+
+	problems, err := verifyBooklet(config, bookletFile, printReady)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("verification failed: %d misplaced pages", len(problems))
+	}
+	return nil
+}
+
+// runNumber numbers the pages of a PDF without imposing it
+func (cli *CLI) runNumber(args []string) error {
+	var (
+		outputFile string = "numbered.pdf"
+		config            = &BookletConfig{}
+	)
+
+	numberFlags := flag.NewFlagSet("number", flag.ExitOnError)
+	numberFlags.StringVar(&config.InputFile, "input", "", "Input PDF file (required)")
+	numberFlags.StringVar(&config.InputFile, "i", "", "Input PDF file (shorthand)")
+	numberFlags.StringVar(&outputFile, "output", "numbered.pdf", "Numbered PDF file")
+	numberFlags.StringVar(&outputFile, "o", "numbered.pdf", "Numbered PDF file (shorthand)")
+	numberFlags.StringVar(&config.Numbering, "numbering", "", "Page numbering rules, e.g. 1-4=roman;5-=arabic,start=1 (required)")
+	numberFlags.StringVar(&config.NumberSkip, "number-skip", "", "Pages that don't show their number (blank, chapter)")
+	numberFlags.StringVar(&config.Numerals, "numerals", NumeralsWestern, "Numeral system of the page numbers")
+	numberFlags.StringVar(&config.FontFile, "font", defaultFontFile, "Font the page numbers are stamped with")
+
+	err := numberFlags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if config.InputFile == "" {
+		return fmt.Errorf("input file is required")
+	}
+	if config.Numbering == "" {
+		return fmt.Errorf("numbering rules are required")
+	}
+	if !isValidOption(config.Numerals, validNumeralSystems) {
+		return fmt.Errorf("numerals must be western, arabic-indic, persian, devanagari, or roman, got %s", config.Numerals)
+	}
+	err = checkNumeralGlyphs(config.FontFile, config.Numerals)
+	if err != nil {
+		return err
+	}
+
+	pages, err := readPageInfo(config.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read page info: %w", err)
+	}
+	order := make([]int, len(pages))
+	for i := range order {
+		order[i] = i + 1
+	}
+	chapters, err := numberingChapters(config, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copying %s to %s\n", config.InputFile, outputFile)
+	_, err = applyNumbering(outputFile, order, config.Numbering, config.NumberSkip, chapters, config.Numerals)
+	return err
+}
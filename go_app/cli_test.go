@@ -174,4 +174,443 @@ func TestCLITempDirCreation(t *testing.T) {
 	} else {
 		t.Logf("Got expected error for invalid output path: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestCLIInvalidScaling(t *testing.T) {
+	cli := &CLI{}
+
+	// Test with invalid scaling policy
+	args := []string{"cmd", "-input", "test.pdf", "-scale", "stretch"}
+	err := cli.Run(args)
+	if err == nil {
+		t.Error("Expected error for invalid scale value, got nil")
+	} else if !strings.Contains(err.Error(), "scale must be") {
+		t.Errorf("Expected error about scale, got: %v", err)
+	}
+
+	// Test with invalid alignment
+	args = []string{"cmd", "-input", "test.pdf", "-align", "middle"}
+	err = cli.Run(args)
+	if err == nil {
+		t.Error("Expected error for invalid align value, got nil")
+	} else if !strings.Contains(err.Error(), "align must be") {
+		t.Errorf("Expected error about align, got: %v", err)
+	}
+}
+
+func TestCLICalibrate(t *testing.T) {
+	cli := &CLI{}
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+
+	// Save a measured offset to a printer profile
+	args := []string{"cmd", "calibrate", "-config", configFile, "-printer", "office", "-back-offset", "1.5,0"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to save back offset: %v", err)
+	}
+
+	profile, err := loadPrinterProfile(configFile, "office")
+	if err != nil || profile.BackOffsetX != 1.5 {
+		t.Errorf("Expected saved back offset 1.5, got %+v (%v)", profile, err)
+	}
+
+	// A scale on its own is saved and keeps the offset
+	args = []string{"cmd", "calibrate", "-config", configFile, "-printer", "office", "-back-scale", "1.002"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to save back scale: %v", err)
+	}
+	profile, err = loadPrinterProfile(configFile, "office")
+	if err != nil || profile.BackScale != 1.002 || profile.BackOffsetX != 1.5 {
+		t.Errorf("Expected saved back scale 1.002 with offset 1.5, got %+v (%v)", profile, err)
+	}
+
+	// An offset without a printer name can't be saved
+	args = []string{"cmd", "calibrate", "-back-offset", "1.5,0"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "printer name is required") {
+		t.Errorf("Expected error about missing printer name, got: %v", err)
+	}
+
+	// Invalid pattern
+	args = []string{"cmd", "calibrate", "-pattern", "dots"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "pattern must be") {
+		t.Errorf("Expected error about pattern, got: %v", err)
+	}
+}
+
+func TestCLIPrinterOptions(t *testing.T) {
+	cli := &CLI{}
+
+	// Unknown sheet size
+	args := []string{"cmd", "-input", "test.pdf", "-sheet", "B5"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "unknown sheet size") {
+		t.Errorf("Expected error about sheet size, got: %v", err)
+	}
+
+	// Missing printer profile
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	args = []string{"cmd", "-input", "test.pdf", "-printer", "office", "-config", configFile}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected error about missing printer profile, got: %v", err)
+	}
+}
+
+func TestCLICover(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "cover", "-front", "front.png", "-page-count", "112", "-gsm", "80"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected cover to be built, got: %v", err)
+	}
+
+	args = []string{"cmd", "cover", "-page-count", "112", "-gsm", "80"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "front cover file is required") {
+		t.Errorf("Expected error about missing front cover, got: %v", err)
+	}
+
+	args = []string{"cmd", "cover", "-front", "front.png", "-page-count", "112"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "caliper or GSM") {
+		t.Errorf("Expected error about missing paper thickness, got: %v", err)
+	}
+}
+
+func TestCLICoverMode(t *testing.T) {
+	cli := &CLI{}
+
+	for _, mode := range []string{CoverNone, CoverSelf, CoverSeparate} {
+		args := []string{"cmd", "-i", "test.pdf", "-cover", mode}
+		if err := cli.Run(args); err != nil {
+			t.Errorf("Expected cover mode %s to be accepted, got: %v", mode, err)
+		}
+	}
+
+	args := []string{"cmd", "-i", "test.pdf", "-cover", "wrap"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "cover must be none, self, or separate") {
+		t.Errorf("Expected error about invalid cover mode, got: %v", err)
+	}
+}
+
+func TestCLIPadding(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-padding", "front=0,aligned,before-last"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected padding policy to be accepted, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-padding", "middle"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "unknown padding option") {
+		t.Errorf("Expected error about unknown padding option, got: %v", err)
+	}
+}
+
+func TestCLIChaptersRecto(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-chapters-recto", "-cover", CoverSeparate}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected chapters on a recto to be accepted, got: %v", err)
+	}
+}
+
+func TestCLIVerify(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-d", "LTR", "-face", "down"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to impose the booklet: %v", err)
+	}
+
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-d", "LTR", "-print-ready", "print_ready", "-face", "down"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the imposed output to verify, got: %v", err)
+	}
+
+	// Verifying with other options than the booklet was made with is refused
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-d", "LTR", "-print-ready", "print_ready", "-face", "up"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "face=down, not up") {
+		t.Errorf("Expected error about the face the output was made with, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-d", "LTR", "-imposition", "octavo"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to impose the octavo: %v", err)
+	}
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-d", "LTR"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "layout=imposition:octavo, not booklet") {
+		t.Errorf("Expected error about the template the output was made with, got: %v", err)
+	}
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-d", "LTR", "-imposition", "octavo"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "not imposition templates") {
+		t.Errorf("Expected error about verifying an imposition template, got: %v", err)
+	}
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-d", "LTR", "-perfect"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "perfect-bound") {
+		t.Errorf("Expected error about verifying perfect binding, got: %v", err)
+	}
+
+	args = []string{"cmd", "verify", "-d", "LTR"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "input file is required") {
+		t.Errorf("Expected error about missing input file, got: %v", err)
+	}
+}
+
+func TestCLINumbering(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "number", "-i", "test.pdf", "-numbering", "1-4=roman;5-=arabic", "-number-skip", "chapter"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected standalone numbering to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-numbering", "1-4=roman;5-=arabic", "-number-skip", "blank"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected numbering before imposition to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-numbering", "1-=hebrew"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "numbering style must be one of") {
+		t.Errorf("Expected error about invalid numbering style, got: %v", err)
+	}
+
+	args = []string{"cmd", "number", "-i", "test.pdf"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "numbering rules are required") {
+		t.Errorf("Expected error about missing numbering rules, got: %v", err)
+	}
+}
+
+func TestCLIRunningHeads(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-running-heads", "-title", "Voyages"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected running heads from the outline to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-heads-file", "missing-heads.txt"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "failed to read heads file") {
+		t.Errorf("Expected error about the missing heads file, got: %v", err)
+	}
+}
+
+func TestCLINumerals(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-numerals", "roman", "-font", filepath.Join(sourceDir, "../fonts/BigBlueTermPlusNerdFontMono-Regular.ttf")}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected roman numerals with the bundled font to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-numerals", "arabic-indic", "-font", filepath.Join(sourceDir, "../fonts/BigBlueTermPlusNerdFontMono-Regular.ttf")}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "no glyphs for arabic-indic numerals") {
+		t.Errorf("Expected error about missing Arabic-Indic glyphs, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-numerals", "klingon"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "numerals must be") {
+		t.Errorf("Expected error about invalid numerals, got: %v", err)
+	}
+}
+
+func TestCLIDirectionAuto(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-direction", "auto"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected -direction auto to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-direction", "up"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "RTL, LTR, or auto") {
+		t.Errorf("Expected error about invalid direction, got: %v", err)
+	}
+}
+
+func TestCLIBinding(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-binding", "top", "-duplex", "long", "-marks", "spine"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected top binding to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-binding", "bottom"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "binding must be") {
+		t.Errorf("Expected error about invalid binding, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-binding", "left"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected left binding without -direction to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-binding", "left", "-d", "RTL"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "reading direction") {
+		t.Errorf("Expected error about the reading direction, got: %v", err)
+	}
+}
+
+func TestCLIImposition(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-imposition", "octavo", "-debug-overlay"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the octavo template to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-imposition", "sextodecimo"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected error about an unknown template, got: %v", err)
+	}
+}
+
+func TestCLIFoldedQuarto(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-imposition", "quarto", "-marks", "spine"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the folded quarto to succeed, got: %v", err)
+	}
+}
+
+func TestCLICutStack(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-p", "4", "-cut-stack", "-duplex", "long", "-duplex-scope", "book"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected 4-up cut-and-stack to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-cut-stack"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "pages per sheet") {
+		t.Errorf("Expected error about pages per sheet, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-p", "8", "-cut-stack"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to impose the cut stacks: %v", err)
+	}
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-p", "8", "-cut-stack", "-print-ready", "print_ready"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the cut stacks to verify, got: %v", err)
+	}
+}
+
+func TestCLIRepeat(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-p", "4", "-repeat", "-duplex", "short"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected 4-up step-and-repeat to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-p", "4", "-repeat", "-cut-stack"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "cut-and-stack") {
+		t.Errorf("Expected error about cut-and-stack, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-p", "2", "-repeat"}
+	if err := cli.Run(args); err != nil {
+		t.Fatalf("Failed to impose the repeated copies: %v", err)
+	}
+	args = []string{"cmd", "verify", "-i", "test.pdf", "-p", "2", "-repeat", "-print-ready", "print_ready"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the repeated copies to verify, got: %v", err)
+	}
+}
+
+func TestCLIPerfectBinding(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-perfect", "-p", "2", "-gutter", "4", "-gsm", "90", "-d", "LTR"}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected 2-up perfect binding to succeed, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-perfect", "-p", "4"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "1 or 2 pages per sheet") {
+		t.Errorf("Expected error about pages per sheet, got: %v", err)
+	}
+
+	args = []string{"cmd", "-i", "test.pdf", "-perfect", "-grind", "-1"}
+	if err := cli.Run(args); err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("Expected error about a negative grind, got: %v", err)
+	}
+}
+
+func TestCLIBackOffsetOverridesProfile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	if err := savePrinterProfile(configFile, "office", PrinterProfile{BackOffsetX: 1.5}); err != nil {
+		t.Fatalf("Failed to save printer profile: %v", err)
+	}
+	cli := &CLI{}
+
+	// Capture stdout, read concurrently so a long run can't fill the pipe
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		buf.ReadFrom(r)
+		output <- buf.String()
+	}()
+
+	args := []string{"cmd", "-i", "test.pdf", "-printer", "office", "-config", configFile, "-back-offset", "0,0"}
+	err := cli.Run(args)
+
+	w.Close()
+	os.Stdout = oldStdout
+	out := <-output
+
+	if err != nil {
+		t.Errorf("Expected an explicit zero back offset to succeed, got: %v", err)
+	}
+	if strings.Contains(out, "Back sides shifted") {
+		t.Error("Expected -back-offset 0,0 to override the profile's back offset")
+	}
+}
+
+func TestCLIFoldedPunchTemplate(t *testing.T) {
+	cli := &CLI{}
+
+	args := []string{"cmd", "-i", "test.pdf", "-imposition", "octavo", "-punch-template", filepath.Join(t.TempDir(), "punch.pdf")}
+	if err := cli.Run(args); err != nil {
+		t.Errorf("Expected the octavo punch template to succeed, got: %v", err)
+	}
+}
+
+func TestCLIPreviewSkipsOtherLayouts(t *testing.T) {
+	preview := filepath.Join(t.TempDir(), "preview.pdf")
+	tests := [][]string{
+		{"-imposition", "octavo"},
+		{"-p", "2", "-cut-stack"},
+		{"-p", "2", "-repeat"},
+	}
+
+	for _, extra := range tests {
+		cli := &CLI{}
+
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		output := make(chan string)
+		go func() {
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output <- buf.String()
+		}()
+
+		args := append([]string{"cmd", "-i", "test.pdf", "-preview", preview}, extra...)
+		err := cli.Run(args)
+
+		w.Close()
+		os.Stdout = oldStdout
+		out := <-output
+
+		if err != nil {
+			t.Errorf("For %v, expected success, got: %v", extra, err)
+		}
+		if !strings.Contains(out, "Warning: the fold preview is only written") || strings.Contains(out, "Writing fold preview") {
+			t.Errorf("For %v, expected the fold preview skipped with a warning", extra)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import "fmt"
+
+// Section mark modes
+const (
+	MarkFolio = "folio" // Folio numbers stamped at shifting offsets (addSectionMarking)
+	MarkSpine = "spine" // Stepped collation blocks on the spine fold
+)
+
+var validMarkModes = []string{MarkFolio, MarkSpine}
+
+// Collation block sizes in mm, as printed on the final sheet
+const (
+	collationBlockWidth = 3.0 // Width across the fold
+	collationMinHeight  = 1.5 // Blocks smaller than this are hard to see on the spine
+)
+
+// collationEndAllowance is the fraction of the spine left free at head and tail
+const collationEndAllowance = 0.05
+
+// CollationMark is a solid block on the spine fold of a signature's outer sheet
+//
+// Positions are in points on the booklet page, measured from its lower left
+// corner. The fold runs vertically through the center of the page, or
+// horizontally for top-bound booklets.
+type CollationMark struct {
+	Signature int
+	Side      int // Booklet page the block is drawn on
+	X, Y      float64
+	Width     float64
+	Height    float64
+}
+
+// collationMarks computes one block per signature, stepping down the spine
+//
+// The usable spine (without a 5% allowance at head and tail) is divided by
+// the signature count, so the blocks of a stacked book block form a diagonal.
+// On a top fold the blocks step from left to right.
+func collationMarks(signatures, sidesPerSignature, pagesPerSheet int, pageWidth, pageHeight float64, binding string) []CollationMark {
+	if signatures <= 0 {
+		return nil
+	}
+
+	spine := pageHeight
+	if !foldIsVertical(binding) {
+		spine = pageWidth
+	}
+	endMargin := spine * collationEndAllowance
+	step := (spine - 2*endMargin) / float64(signatures)
+	width := collationBlockWidth * mmToPoints / nupScale(pagesPerSheet)
+
+	marks := make([]CollationMark, signatures)
+	for i := range marks {
+		mark := CollationMark{
+			Signature: i + 1,
+			Side:      i*sidesPerSignature + 1, // Front of the outer sheet
+		}
+		if foldIsVertical(binding) {
+			top := pageHeight - endMargin - float64(i)*step
+			mark.X, mark.Y = pageWidth/2-width/2, top-step
+			mark.Width, mark.Height = width, step
+		} else {
+			mark.X, mark.Y = endMargin+float64(i)*step, pageHeight/2-width/2
+			mark.Width, mark.Height = step, width
+		}
+		marks[i] = mark
+	}
+	return marks
+}
+
+// addCollationMarks draws the stepped collation blocks on the spine of each signature
+func addCollationMarks(pdfFile string, totalSides, sidesPerSignature, pagesPerSheet int, sheetWidth, sheetHeight float64, binding string) ([]CollationMark, error) {
+	if sidesPerSignature <= 0 {
+		return nil, fmt.Errorf("invalid signature size %d", sidesPerSignature)
+	}
+	signatures := (totalSides + sidesPerSignature - 1) / sidesPerSignature
+
+	// The booklet page is the sheet folded along its short side, landscape for side binding
+	pageWidth, pageHeight := orientSheet(sheetWidth, sheetHeight, foldIsVertical(binding))
+	marks := collationMarks(signatures, sidesPerSignature, pagesPerSheet, pageWidth, pageHeight, binding)
+
+	fmt.Printf("Adding spine collation marks to %s, signatures: %d\n", pdfFile, signatures)
+	if len(marks) > 0 {
+		length := marks[0].Height
+		if !foldIsVertical(binding) {
+			length = marks[0].Width
+		}
+		height := length * nupScale(pagesPerSheet) / mmToPoints
+		fmt.Printf("  Block size %.1fx%.1f mm, stepping %.1f mm per signature\n", collationBlockWidth, height, height)
+		if height < collationMinHeight {
+			fmt.Printf("Warning: collation blocks are only %.1f mm high, consider fewer signatures\n", height)
+		}
+	}
+	for _, mark := range marks {
+		if foldIsVertical(binding) {
+			fmt.Printf("  Signature %02d: block on page %d at %.1f points from the tail\n", mark.Signature, mark.Side, mark.Y)
+		} else {
+			fmt.Printf("  Signature %02d: block on page %d at %.1f points from the left end of the top fold\n", mark.Signature, mark.Side, mark.X)
+		}
+	}
+
+	// In a real implementation, this would draw a filled rectangle on each page
+	return marks, nil
+}
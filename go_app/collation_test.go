@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollationMarks(t *testing.T) {
+	// 1-up booklet page: landscape A4 folded across its short side
+	marks := collationMarks(4, 16, 1, a4Height, a4Width, BindingLeft)
+	if len(marks) != 4 {
+		t.Fatalf("Expected one mark per signature, got %d", len(marks))
+	}
+
+	step := a4Width * 0.9 / 4
+	for i, mark := range marks {
+		if mark.Side != i*16+1 {
+			t.Errorf("Expected signature %d mark on page %d, got %d", mark.Signature, i*16+1, mark.Side)
+		}
+		if math.Abs(mark.Height-step) > 0.001 {
+			t.Errorf("Expected block height %.2f, got %.2f", step, mark.Height)
+		}
+		if math.Abs(mark.X+mark.Width/2-a4Height/2) > 0.001 {
+			t.Errorf("Expected block centered on the fold, got x=%.2f", mark.X)
+		}
+	}
+
+	// Each block starts where the previous one ends
+	for i := 1; i < len(marks); i++ {
+		if math.Abs(marks[i].Y+marks[i].Height-marks[i-1].Y) > 0.001 {
+			t.Errorf("Expected signature %d to step down from signature %d", i+1, i)
+		}
+	}
+	if math.Abs(marks[0].Y+marks[0].Height-a4Width*0.95) > 0.001 {
+		t.Errorf("Expected the first block to start below the head allowance, got %.2f", marks[0].Y+marks[0].Height)
+	}
+}
+
+func TestAddCollationMarks(t *testing.T) {
+	marks, err := addCollationMarks("booklet.pdf", 50, 16, 1, a4Width, a4Height, BindingLeft)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(marks) != 4 {
+		t.Errorf("Expected 4 signatures for 50 sides of 16, got %d", len(marks))
+	}
+
+	if _, err := addCollationMarks("booklet.pdf", 50, 0, 1, a4Width, a4Height, BindingLeft); err == nil {
+		t.Error("Expected error for an invalid signature size, got nil")
+	}
+}
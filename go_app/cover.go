@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// paperBulk is the typical bulk of uncoated book paper in cm³/g, used to estimate caliper from GSM
+const paperBulk = 1.25
+
+// defaultBleed is the cover bleed along every edge in mm
+const defaultBleed = 3.0
+
+// CoverSpec describes the wraparound cover to build
+type CoverSpec struct {
+	FrontFile     string // Front image or PDF (first page is used)
+	BackFile      string // Optional back image or PDF
+	SpineText     string
+	PageCount     int     // Final page count of the book after padding
+	Caliper       float64 // Paper thickness per leaf in mm
+	GSM           float64 // Paper weight, used when the caliper is unknown
+	PagesPerSheet int     // Interior layout, which decides the trim size
+	InteriorSheet string  // Sheet the interior is printed on
+	Sheet         string  // Sheet the cover is printed on
+	Bleed         float64 // Bleed in mm
+	Direction     string  // "RTL" puts the front panel on the left
+}
+
+// CoverLayout is the computed geometry of a wraparound cover, in points
+type CoverLayout struct {
+	Width      float64
+	Height     float64
+	TrimWidth  float64 // Size of one cover panel without bleed
+	TrimHeight float64
+	SpineWidth float64
+	Bleed      float64
+	FrontX     float64   // Left edge of the front panel
+	BackX      float64   // Left edge of the back panel
+	SpineX     float64   // Left edge of the spine
+	ScoreLines []float64 // X positions of the folds around the spine
+
+	SheetWidth     float64
+	SheetHeight    float64
+	Fits           bool
+	RotatedOnSheet bool // The cover fits only with the sheet turned to landscape
+
+	SpineTextRotation   int
+	EstimatedFromWeight bool // The caliper was estimated from GSM
+}
+
+// paperCaliper returns the caliper in mm, estimating it from GSM if needed
+func paperCaliper(caliper, gsm float64) (float64, bool) {
+	if caliper > 0 {
+		return caliper, false
+	}
+	return gsm * paperBulk / 1000, true
+}
+
+// spineWidth returns the spine width in mm for the final page count
+func spineWidth(pageCount int, caliper float64) float64 {
+	// Every leaf carries two pages
+	leaves := (pageCount + 1) / 2
+	return float64(leaves) * caliper
+}
+
+// computeCoverLayout lays out back, spine and front panels with bleed and score lines
+func computeCoverLayout(spec CoverSpec) (CoverLayout, error) {
+	if spec.PageCount <= 0 {
+		return CoverLayout{}, fmt.Errorf("page count must be positive, got %d", spec.PageCount)
+	}
+	if spec.Caliper <= 0 && spec.GSM <= 0 {
+		return CoverLayout{}, fmt.Errorf("paper caliper or GSM is required")
+	}
+
+	interiorWidth, interiorHeight, err := sheetSize(spec.InteriorSheet)
+	if err != nil {
+		return CoverLayout{}, err
+	}
+	sheetWidth, sheetHeight, err := sheetSize(spec.Sheet)
+	if err != nil {
+		return CoverLayout{}, err
+	}
+
+	caliper, estimated := paperCaliper(spec.Caliper, spec.GSM)
+	trimWidth, trimHeight := slotSize(interiorWidth, interiorHeight, spec.PagesPerSheet)
+	spine := spineWidth(spec.PageCount, caliper) * mmToPoints
+	bleed := spec.Bleed * mmToPoints
+
+	layout := CoverLayout{
+		Width:               2*bleed + 2*trimWidth + spine,
+		Height:              2*bleed + trimHeight,
+		TrimWidth:           trimWidth,
+		TrimHeight:          trimHeight,
+		SpineWidth:          spine,
+		Bleed:               bleed,
+		SpineX:              bleed + trimWidth,
+		SheetWidth:          sheetWidth,
+		SheetHeight:         sheetHeight,
+		SpineTextRotation:   -90, // Reads top to bottom
+		EstimatedFromWeight: estimated,
+	}
+
+	// The back panel sits left of the spine for LTR books, right of it for RTL books
+	if spec.Direction == "RTL" {
+		layout.FrontX, layout.BackX = bleed, bleed+trimWidth+spine
+	} else {
+		layout.BackX, layout.FrontX = bleed, bleed+trimWidth+spine
+	}
+	layout.ScoreLines = []float64{layout.SpineX, layout.SpineX + spine}
+
+	switch {
+	case layout.Width <= sheetWidth && layout.Height <= sheetHeight:
+		layout.Fits = true
+	case layout.Width <= sheetHeight && layout.Height <= sheetWidth:
+		layout.Fits = true
+		layout.RotatedOnSheet = true
+	}
+	return layout, nil
+}
+
+// isPDFFile reports whether the cover artwork is a PDF rather than an image
+func isPDFFile(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".pdf")
+}
+
+// writeCover writes the wraparound cover PDF
+func writeCover(outputFile string, spec CoverSpec, layout CoverLayout) {
+	fmt.Printf("Writing cover to %s\n", outputFile)
+	if layout.EstimatedFromWeight {
+		fmt.Printf("  Caliper estimated from %.0f gsm\n", spec.GSM)
+	}
+	fmt.Printf("  Pages: %d, spine width: %.2f mm\n", spec.PageCount, layout.SpineWidth/mmToPoints)
+	fmt.Printf("  Cover size: %.1fx%.1f mm including %.1f mm bleed\n",
+		layout.Width/mmToPoints, layout.Height/mmToPoints, spec.Bleed)
+	fmt.Printf("  Score lines at %.1f mm and %.1f mm\n", layout.ScoreLines[0]/mmToPoints, layout.ScoreLines[1]/mmToPoints)
+
+	front := "image"
+	if isPDFFile(spec.FrontFile) {
+		front = "PDF page 1"
+	}
+	fmt.Printf("  Front: %s (%s)\n", spec.FrontFile, front)
+	if spec.BackFile != "" {
+		fmt.Printf("  Back: %s\n", spec.BackFile)
+	}
+	if spec.SpineText != "" {
+		fmt.Printf("  Spine text: %q, rotated %d degrees\n", spec.SpineText, layout.SpineTextRotation)
+	}
+
+	if !layout.Fits {
+		fmt.Printf("Warning: cover (%.1fx%.1f mm) does not fit on a %s sheet (%.1fx%.1f mm)\n",
+			layout.Width/mmToPoints, layout.Height/mmToPoints, spec.Sheet,
+			layout.SheetWidth/mmToPoints, layout.SheetHeight/mmToPoints)
+	} else if layout.RotatedOnSheet {
+		fmt.Printf("  Placed on a landscape %s sheet\n", spec.Sheet)
+	}
+
+	// In a real implementation, this would draw the panels, spine text, score lines and crop marks
+}
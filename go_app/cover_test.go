@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpineWidth(t *testing.T) {
+	if width := spineWidth(112, 0.1); math.Abs(width-5.6) > 0.0001 {
+		t.Errorf("Expected 5.6 mm spine for 112 pages at 0.1 mm, got %.4f", width)
+	}
+
+	caliper, estimated := paperCaliper(0, 80)
+	if !estimated || math.Abs(caliper-0.1) > 0.0001 {
+		t.Errorf("Expected 80 gsm to estimate a 0.1 mm caliper, got %.4f (estimated=%v)", caliper, estimated)
+	}
+
+	caliper, estimated = paperCaliper(0.12, 80)
+	if estimated || caliper != 0.12 {
+		t.Errorf("Expected the given caliper to be used, got %.4f (estimated=%v)", caliper, estimated)
+	}
+}
+
+func TestComputeCoverLayout(t *testing.T) {
+	spec := CoverSpec{
+		PageCount:     112,
+		Caliper:       0.1,
+		PagesPerSheet: 1,
+		InteriorSheet: "A4",
+		Sheet:         "A3",
+		Bleed:         3,
+		Direction:     "LTR",
+	}
+
+	layout, err := computeCoverLayout(spec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Two A5 panels, a 5.6 mm spine and 3 mm bleed on each side
+	a5Width, a5Height := slotSize(a4Width, a4Height, 1)
+	expectedWidth := 2*a5Width + (5.6+6)*mmToPoints
+	if math.Abs(layout.Width-expectedWidth) > 0.01 || math.Abs(layout.Height-(a5Height+6*mmToPoints)) > 0.01 {
+		t.Errorf("Unexpected cover size %.2fx%.2f", layout.Width, layout.Height)
+	}
+	if layout.BackX >= layout.FrontX {
+		t.Error("Expected the back panel left of the front panel for LTR")
+	}
+	if math.Abs(layout.ScoreLines[1]-layout.ScoreLines[0]-5.6*mmToPoints) > 0.01 {
+		t.Errorf("Expected score lines 5.6 mm apart, got %+v", layout.ScoreLines)
+	}
+	if !layout.Fits || !layout.RotatedOnSheet {
+		t.Errorf("Expected the A5 cover to fit on a landscape A3 sheet, got fits=%v rotated=%v", layout.Fits, layout.RotatedOnSheet)
+	}
+
+	spec.Direction = "RTL"
+	layout, _ = computeCoverLayout(spec)
+	if layout.FrontX >= layout.BackX {
+		t.Error("Expected the front panel left of the back panel for RTL")
+	}
+
+	spec.Sheet = "A4"
+	layout, _ = computeCoverLayout(spec)
+	if layout.Fits {
+		t.Error("Expected the A5 wraparound cover not to fit on A4")
+	}
+}
+
+func TestComputeCoverLayoutErrors(t *testing.T) {
+	if _, err := computeCoverLayout(CoverSpec{PageCount: 0, Caliper: 0.1}); err == nil {
+		t.Error("Expected error for a zero page count, got nil")
+	}
+	if _, err := computeCoverLayout(CoverSpec{PageCount: 100}); err == nil {
+		t.Error("Expected error without caliper or GSM, got nil")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Cover modes for the first and last input pages
+const (
+	CoverNone     = "none"     // Covers are imposed like any other page, after the front blanks
+	CoverSelf     = "self"     // Covers become the outer wrap of the book
+	CoverSeparate = "separate" // Covers go to their own file and only the interior is imposed
+)
+
+var validCoverModes = []string{CoverNone, CoverSelf, CoverSeparate}
+
+// coverPageOrder returns the input page on every prepared page, 0 for a blank
+//
+// pagesPerSignature is the padding unit, as in paddedPageCount. A self cover
+// keeps the first page in front and moves the last page behind the end blanks,
+// so neither lands inside the first signature. Chapters are the input pages
+// that must start on a recto.
+func coverPageOrder(totalPages, addBlank, pagesPerSignature int, mode string, policy PaddingPolicy, chapters []int) ([]int, PaddingPlan, error) {
+	if mode != "" && mode != CoverNone && totalPages < 2 {
+		return nil, PaddingPlan{}, fmt.Errorf("%s cover needs at least 2 pages, got %d", mode, totalPages)
+	}
+
+	first, last := 1, totalPages
+	switch mode {
+	case CoverSelf:
+		// The cover must be outermost, so no front blanks; the padding goes before the back cover
+		policy.Front, policy.BeforeLast, policy.BeforePage = 0, true, 0
+		if policy.Back < 0 {
+			policy.Aligned = true
+		}
+	case CoverSeparate:
+		first, last = 2, totalPages-1
+	}
+
+	plan, err := planPadding(first, last, addBlank, pagesPerSignature, policy, chapters)
+	if err != nil {
+		return nil, PaddingPlan{}, err
+	}
+	return paddedOrder(first, last, plan, chapterBreaks(chapters, first, last, plan.Front, plan.Before)), plan, nil
+}
+
+// paddedOrder lays out input pages first..last with the blanks of the plan
+//
+// Breaks are the pages preceded by a single blank to start a chapter on a recto;
+// a break at last+1 is a blank after the last page.
+func paddedOrder(first, last int, plan PaddingPlan, breaks []int) []int {
+	order := make([]int, 0, last-first+1+plan.Blanks())
+	endBlanks := func() {
+		for i := 0; i < plan.Back+plan.Fill; i++ {
+			order = append(order, 0)
+		}
+	}
+
+	for i := 0; i < plan.Front; i++ {
+		order = append(order, 0)
+	}
+	breakAt := make(map[int]bool, len(breaks))
+	for _, page := range breaks {
+		breakAt[page] = true
+	}
+	for page := first; page <= last; page++ {
+		if page == plan.Before {
+			endBlanks()
+		}
+		if breakAt[page] {
+			order = append(order, 0)
+		}
+		order = append(order, page)
+	}
+	if plan.Before == 0 {
+		endBlanks()
+	}
+	if breakAt[last+1] {
+		order = append(order, 0)
+	}
+	return order
+}
+
+// coverFileName returns the file the separate cover pages are extracted to
+func coverFileName(outputFile string) string {
+	ext := filepath.Ext(outputFile)
+	return strings.TrimSuffix(outputFile, ext) + "_cover" + ext
+}
+
+// interiorPages returns the input pages that are imposed, at their prepared positions
+func interiorPages(pages []PageInfo, order []int) ([]PageInfo, []int) {
+	byNumber := make(map[int]PageInfo, len(pages))
+	for _, page := range pages {
+		byNumber[page.Number] = page
+	}
+
+	var interior []PageInfo
+	var positions []int
+	for i, number := range order {
+		if page, ok := byNumber[number]; ok {
+			interior = append(interior, page)
+			positions = append(positions, i+1)
+		}
+	}
+	return interior, positions
+}
+
+// applyOrderRotation rotates the imposed pages according to their prepared positions
+func applyOrderRotation(pages []PageInfo, order []int, mode, direction, binding string) []PageInfo {
+	interior, positions := interiorPages(pages, order)
+	rotated := make([]PageInfo, 0, len(interior))
+	for i, page := range interior {
+		// applyRotation places a page at its number plus the offset
+		rotated = append(rotated, applyRotation([]PageInfo{page}, mode, direction, binding, positions[i]-page.Number)...)
+	}
+	return rotated
+}
+
+// prepareOrderedPages writes the prepared PDF in the given page order
+func prepareOrderedPages(inputFile, outputFile string, order []int, mode string) error {
+	fmt.Printf("Preparing booklet pages: %s -> %s, cover: %s, %d pages\n", inputFile, outputFile, mode, len(order))
+	if mode == CoverSelf {
+		fmt.Printf("  Page 1 is the front cover, page %d the back cover\n", order[len(order)-1])
+	}
+
+	// In a real implementation, this would collect the pages with pdfcpu and insert the blanks
+	return nil
+}
+
+// extractCoverPages writes the first and last input pages to their own file
+func extractCoverPages(inputFile, coverFile string, totalPages, interiorCount int) error {
+	fmt.Printf("Extracting cover pages 1 and %d of %s to %s\n", totalPages, inputFile, coverFile)
+	fmt.Printf("  Interior has %d pages; pass -page-count %d to the cover command\n", interiorCount, interiorCount)
+
+	// In a real implementation, this would run "pdfcpu trim -pages 1,l"
+	return nil
+}
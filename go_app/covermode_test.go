@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverPageOrder(t *testing.T) {
+	tests := []struct {
+		name       string
+		totalPages int
+		addBlank   int
+		mode       string
+		expected   []int
+	}{
+		{"none keeps today's blanks", 5, 1, CoverNone, []int{0, 0, 1, 2, 3, 4, 5, 0, 0, 0, 0, 0}},
+		{"none without blanks", 4, 0, CoverNone, []int{1, 2, 3, 4}},
+		{"self wraps the book", 6, 1, CoverSelf, []int{1, 2, 3, 4, 5, 0, 0, 6}},
+		{"self already whole", 8, 1, CoverSelf, []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{"separate drops the covers", 6, 0, CoverSeparate, []int{2, 3, 4, 5}},
+		{"separate with blanks", 6, 1, CoverSeparate, []int{0, 0, 2, 3, 4, 5, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, _, err := coverPageOrder(test.totalPages, test.addBlank, 4, test.mode, defaultPadding, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(order, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, order)
+			}
+		})
+	}
+
+	if _, _, err := coverPageOrder(1, 1, 4, CoverSelf, defaultPadding, nil); err == nil {
+		t.Error("Expected error for a one page self cover, got nil")
+	}
+}
+
+func TestCoverOrderMatchesPaddedCount(t *testing.T) {
+	for total := 1; total <= 40; total++ {
+		order, _, _ := coverPageOrder(total, 1, 16, CoverNone, defaultPadding, nil)
+		if expected := paddedPageCount(total, 1, 16); len(order) != expected {
+			t.Errorf("Expected %d prepared pages for %d input pages, got %d", expected, total, len(order))
+		}
+	}
+}
+
+func TestCoverFileName(t *testing.T) {
+	if name := coverFileName("out/booklet.pdf"); name != "out/booklet_cover.pdf" {
+		t.Errorf("Expected out/booklet_cover.pdf, got %s", name)
+	}
+}
+
+func TestApplyOrderRotation(t *testing.T) {
+	pages := []PageInfo{
+		{Number: 1, Width: 100, Height: 200},
+		{Number: 2, Width: 200, Height: 100},
+		{Number: 3, Width: 100, Height: 200},
+		{Number: 4, Width: 200, Height: 100},
+	}
+
+	// Separate covers leave pages 2 and 3 on prepared pages 1 and 2
+	order := []int{2, 3, 0, 0}
+	rotated := applyOrderRotation(pages, order, RotateCW, "LTR", BindingLeft)
+	if len(rotated) != 2 || rotated[0].Number != 2 {
+		t.Fatalf("Expected only the interior pages, got %+v", rotated)
+	}
+	if rotated[0].Rotate != normalizeRotation(rotationFor(1, RotateCW, "LTR")) {
+		t.Errorf("Expected page 2 rotated as a recto, got %d", rotated[0].Rotate)
+	}
+
+	// A self cover moves the last page to the end of the padded book
+	order = []int{1, 2, 3, 0, 0, 0, 0, 4}
+	rotated = applyOrderRotation(pages, order, RotateCW, "LTR", BindingLeft)
+	if last := rotated[len(rotated)-1]; last.Rotate != normalizeRotation(rotationFor(8, RotateCW, "LTR")) {
+		t.Errorf("Expected the back cover rotated as a verso, got %d", last.Rotate)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import "fmt"
+
+// emptyCell marks an n-up cell cut-and-stack leaves unprinted at the end of the last stack
+var emptyCell = [2]int{-1, -1}
+
+// StackLabel is the label printed in the corner of a cut-and-stack cell
+type StackLabel struct {
+	File     string
+	Side     int // Side in the print-ready file, starting at 1
+	Cell     int // n-up cell, left to right, top to bottom, starting at 1
+	Stack    int
+	Position int // Sheet within the stack, from the top
+	Text     string
+}
+
+// validateCutStack checks that the layout has n-up cells to cut into stacks
+func validateCutStack(pagesPerSheet int, imposition string) error {
+	if imposition != "" {
+		return fmt.Errorf("cut-and-stack can't be combined with an imposition template")
+	}
+	if pagesPerSheet != 2 && pagesPerSheet != 4 && pagesPerSheet != 8 {
+		return fmt.Errorf("cut-and-stack needs 2, 4, or 8 pages per sheet, got %d", pagesPerSheet)
+	}
+	return nil
+}
+
+// cutStackSheets assigns the booklet pages of a print run so every n-up cell cuts into a contiguous stack
+//
+// With S sheets, cell c of sheet k carries folio c*S+k: the first stack holds
+// the first S folios, the second the next S and so on. Cells past the last
+// folio stay empty (page 0).
+func cutStackSheets(firstPage, sides, pagesPerSheet int) ([]PrintSide, []PrintSide) {
+	folios := (sides + 1) / 2
+	sheets := (folios + pagesPerSheet - 1) / pagesPerSheet
+
+	fronts := make([]PrintSide, sheets)
+	backs := make([]PrintSide, sheets)
+	for k := 0; k < sheets; k++ {
+		fronts[k] = PrintSide{Sheet: k + 1}
+		backs[k] = PrintSide{Sheet: k + 1, Back: true}
+		for c := 0; c < pagesPerSheet; c++ {
+			front, back := 0, 0
+			if folio := c*sheets + k; folio < folios {
+				front = firstPage + 2*folio
+				if 2*folio+1 < sides {
+					back = front + 1
+				}
+			}
+			fronts[k].Pages = append(fronts[k].Pages, front)
+			backs[k].Pages = append(backs[k].Pages, back)
+		}
+	}
+	return fronts, backs
+}
+
+// stackOrder puts the folios of one print run, sheet by sheet, in the order of the stacked piles
+func stackOrder(folios []Folio, cells int) []Folio {
+	if cells <= 1 {
+		return folios
+	}
+	sheets := (len(folios) + cells - 1) / cells
+	stacked := make([]Folio, 0, len(folios))
+	for c := 0; c < cells; c++ {
+		for k := 0; k < sheets; k++ {
+			if i := k*cells + c; i < len(folios) && folios[i].Front != emptyCell {
+				stacked = append(stacked, folios[i])
+			}
+		}
+	}
+	return stacked
+}
+
+// stackLabels labels every printed front cell with its stack and its place in the stack
+func stackLabels(files []PrintFile) []StackLabel {
+	var labels []StackLabel
+	for _, file := range files {
+		stackHeight := 0
+		for _, side := range file.Sides {
+			if !side.Back && side.Sheet > stackHeight {
+				stackHeight = side.Sheet
+			}
+		}
+		for i, side := range file.Sides {
+			if side.Back {
+				continue
+			}
+			for cell, page := range side.Pages {
+				if page == 0 {
+					continue
+				}
+				labels = append(labels, StackLabel{
+					File:     file.Name,
+					Side:     i + 1,
+					Cell:     cell + 1,
+					Stack:    cell + 1,
+					Position: side.Sheet,
+					Text:     fmt.Sprintf("Stack %d · %d/%d", cell+1, side.Sheet, stackHeight),
+				})
+			}
+		}
+	}
+	return labels
+}
+
+// cutOrder returns the printed instructions for cutting and stacking the sheets
+func cutOrder(pagesPerSheet int, perfect bool) []string {
+	lines := []string{
+		fmt.Sprintf("Keep the printed sheets in order and cut the whole pile into %d stacks, cells numbered left to right, top to bottom", pagesPerSheet),
+		"Cell 1 is stack 1, cell 2 is stack 2 and so on; the label in each corner names the stack",
+	}
+	for stack := 1; stack < pagesPerSheet; stack++ {
+		lines = append(lines, fmt.Sprintf("Put stack %d on top of stack %d, keeping both face up", stack, stack+1))
+	}
+	if perfect {
+		return append(lines, "Jog the pile square on the glued edge, the first page on top")
+	}
+	return append(lines, "Fold the folios from the top of the pile in signature order")
+}
+
+// addStackLabels stamps the stack labels in the corner of each cell and prints the cut order
+//
+// Like the signature labels, the stamped digits follow the numeral system;
+// the words stay English as the cut order does, there are no translations.
+func addStackLabels(labels []StackLabel, pagesPerSheet int, perfect bool, numerals string) error {
+	for i := range labels {
+		labels[i].Text = localizeDigits(labels[i].Text, numerals)
+	}
+
+	fmt.Printf("Cut-and-stack, %d stacks:\n", pagesPerSheet)
+	for i, line := range cutOrder(pagesPerSheet, perfect) {
+		fmt.Printf("  %d. %s\n", i+1, line)
+	}
+	for _, label := range labels {
+		fmt.Printf("  %s side %d cell %d: %q\n", label.File, label.Side, label.Cell, label.Text)
+	}
+
+	// In a real implementation, this would stamp each label in the bottom outer corner of its cell
+	// and add the cut order as a cover page of the first front file
+	return nil
+}
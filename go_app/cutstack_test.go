@@ -0,0 +1,136 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCutStackSheets(t *testing.T) {
+	tests := []struct {
+		sides, pagesPerSheet int
+		fronts, backs        [][]int
+	}{
+		// 8 folios on 2 sheets: stack 1 holds folios 1-2, stack 2 folios 3-4 and so on
+		{16, 4, [][]int{{1, 5, 9, 13}, {3, 7, 11, 15}}, [][]int{{2, 6, 10, 14}, {4, 8, 12, 16}}},
+		// 5 folios leave the last cells empty
+		{10, 4, [][]int{{1, 5, 9, 0}, {3, 7, 0, 0}}, [][]int{{2, 6, 10, 0}, {4, 8, 0, 0}}},
+		{8, 2, [][]int{{1, 5}, {3, 7}}, [][]int{{2, 6}, {4, 8}}},
+	}
+
+	for _, test := range tests {
+		fronts, backs := cutStackSheets(1, test.sides, test.pagesPerSheet)
+		var gotFronts, gotBacks [][]int
+		for i := range fronts {
+			gotFronts = append(gotFronts, fronts[i].Pages)
+			gotBacks = append(gotBacks, backs[i].Pages)
+			if fronts[i].Sheet != i+1 || !backs[i].Back {
+				t.Errorf("Expected sheet %d front and back, got %+v and %+v", i+1, fronts[i], backs[i])
+			}
+		}
+		if !reflect.DeepEqual(gotFronts, test.fronts) || !reflect.DeepEqual(gotBacks, test.backs) {
+			t.Errorf("Expected %v / %v for %d sides %d-up, got %v / %v",
+				test.fronts, test.backs, test.sides, test.pagesPerSheet, gotFronts, gotBacks)
+		}
+	}
+}
+
+func TestStackOrder(t *testing.T) {
+	folio := func(front int) Folio { return Folio{Front: [2]int{front, front}} }
+	sheets := []Folio{folio(1), folio(3), folio(2), {Front: emptyCell, Back: emptyCell}}
+
+	var fronts []int
+	for _, f := range stackOrder(sheets, 2) {
+		fronts = append(fronts, f.Front[0])
+	}
+	if !reflect.DeepEqual(fronts, []int{1, 2, 3}) {
+		t.Errorf("Expected the stacks to read 1, 2, 3, got %v", fronts)
+	}
+}
+
+func TestPrintedFoliosCutStack(t *testing.T) {
+	order := sequentialOrder(40)
+	sides := imposeBookletSides(order, 16, "LTR")
+
+	for _, pagesPerSheet := range []int{2, 4, 8} {
+		for _, opts := range []PrintOptions{
+			{Mode: DuplexManual, Face: FaceUp, CutStack: true},
+			{Mode: DuplexLong, Scope: DuplexPerSignature, CutStack: true},
+			{Mode: DuplexShort, Scope: DuplexPerBook, CutStack: true},
+		} {
+			files := markPrintFiles("print_ready", planPrintFiles("booklet.pdf", len(sides), 16, pagesPerSheet, opts), sides)
+			folios := printedFolios(files, opts)
+			numberFolios(folios, 8, pagesPerSheet)
+			read := foldReadingOrder(folios, 8, "LTR")
+			if problems := checkReadingOrder(read, order); len(problems) > 0 {
+				t.Errorf("%d-up %+v: unexpected problems %v", pagesPerSheet, opts, problems)
+			}
+		}
+	}
+
+	// Interleaving the piles as for the normal layout scrambles the pages
+	opts := PrintOptions{Mode: DuplexManual, Face: FaceUp, CutStack: true}
+	files := markPrintFiles("print_ready", planPrintFiles("booklet.pdf", len(sides), 16, 4, opts), sides)
+	opts.CutStack = false
+	var folios []Folio
+	for _, folio := range printedFolios(files, opts) {
+		if folio.Front != emptyCell {
+			folios = append(folios, folio)
+		}
+	}
+	numberFolios(folios, 8, 4)
+	if problems := checkReadingOrder(foldReadingOrder(folios, 8, "LTR"), order); len(problems) == 0 {
+		t.Error("Expected problems when the stacks are interleaved, got none")
+	}
+}
+
+func TestStackLabels(t *testing.T) {
+	files := planPrintFiles("book.pdf", 10, 0, 4, PrintOptions{Mode: DuplexManual, CutStack: true})
+	labels := stackLabels(files)
+
+	// 5 folios: stack 1 and 2 have 2 sheets, stack 3 one
+	if len(labels) != 5 {
+		t.Fatalf("Expected 5 labels, got %d", len(labels))
+	}
+	first := labels[0]
+	if first.File != "1_F_book_1.pdf" || first.Stack != 1 || first.Position != 1 || first.Text != "Stack 1 · 1/2" {
+		t.Errorf("Expected the first cell labelled stack 1 sheet 1 of 2, got %+v", first)
+	}
+	for _, label := range labels {
+		if strings.Contains(label.File, "_B_") {
+			t.Errorf("Expected labels on the fronts only, got %+v", label)
+		}
+	}
+}
+
+func TestStackLabelsNumerals(t *testing.T) {
+	labels := []StackLabel{{Text: "Stack 2 · 1/3"}}
+	if err := addStackLabels(labels, 4, false, NumeralsArabicIndic); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if labels[0].Text != "Stack ٢ · ١/٣" {
+		t.Errorf("Expected Arabic-Indic digits, got %q", labels[0].Text)
+	}
+}
+
+func TestCutOrder(t *testing.T) {
+	lines := cutOrder(4, false)
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 instructions for 4 stacks, got %d", len(lines))
+	}
+	if lines[2] != "Put stack 1 on top of stack 2, keeping both face up" {
+		t.Errorf("Expected stack 1 to go on stack 2, got %q", lines[2])
+	}
+}
+
+func TestValidateCutStack(t *testing.T) {
+	if err := validateCutStack(4, ""); err != nil {
+		t.Errorf("Expected 4-up to be valid, got %v", err)
+	}
+	if err := validateCutStack(1, ""); err == nil {
+		t.Errorf("Expected an error for 1 page per sheet")
+	}
+	if err := validateCutStack(2, "quarto"); err == nil {
+		t.Errorf("Expected an error with an imposition template")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// DirectionAuto detects the reading direction from the input PDF
+const DirectionAuto = "auto"
+
+// defaultDirection is used when the direction can't be detected
+const defaultDirection = "RTL"
+
+// rtlScripts are the scripts written right to left
+var rtlScripts = []*unicode.RangeTable{unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko}
+
+// viewerDirection returns the /ViewerPreferences /Direction value for a reading direction
+func viewerDirection(direction string) string {
+	if direction == "RTL" {
+		return "R2L"
+	}
+	return "L2R"
+}
+
+// directionFromViewer returns the reading direction of a /Direction value, empty if unknown
+func directionFromViewer(value string) string {
+	switch value {
+	case "R2L":
+		return "RTL"
+	case "L2R":
+		return "LTR"
+	}
+	return ""
+}
+
+// dominantScript returns the direction most letters of the text are written in, empty for no letters
+func dominantScript(text string) string {
+	rtl, ltr := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.IsOneOf(rtlScripts, r):
+			rtl++
+		case unicode.IsLetter(r):
+			ltr++
+		}
+	}
+	switch {
+	case rtl == 0 && ltr == 0:
+		return ""
+	case rtl >= ltr:
+		return "RTL"
+	default:
+		return "LTR"
+	}
+}
+
+// readViewerDirection reads the /ViewerPreferences /Direction entry of the input PDF
+func readViewerDirection(inputFile string) (string, error) {
+	// In a real implementation, this would read the document catalog with pdfcpu;
+	// the simulated input has no viewer preferences
+	return "", nil
+}
+
+// extractText returns the text of the first pages of the input PDF
+func extractText(inputFile string) (string, error) {
+	// In a real implementation, this would extract the content streams of the first pages;
+	// the simulated input has no text layer
+	return "", nil
+}
+
+// detectDirection detects the reading direction from the viewer preferences, then from the text
+//
+// The second result tells where the direction came from.
+func detectDirection(inputFile string) (string, string, error) {
+	value, err := readViewerDirection(inputFile)
+	if err != nil {
+		return "", "", err
+	}
+	if direction := directionFromViewer(value); direction != "" {
+		return direction, "/ViewerPreferences /Direction " + value, nil
+	}
+
+	text, err := extractText(inputFile)
+	if err != nil {
+		return "", "", err
+	}
+	if direction := dominantScript(text); direction != "" {
+		return direction, "dominant script of the text", nil
+	}
+	return defaultDirection, "default, nothing to detect from", nil
+}
+
+// resolveDirection replaces an "auto" reading direction with the detected one
+func resolveDirection(config *BookletConfig) error {
+	if config.ReadingDirection != DirectionAuto {
+		return nil
+	}
+
+	direction, source, err := detectDirection(config.InputFile)
+	if err != nil {
+		return fmt.Errorf("failed to detect reading direction: %w", err)
+	}
+	fmt.Printf("Reading direction: %s (%s)\n", direction, source)
+	config.ReadingDirection = direction
+	return nil
+}
+
+// writeViewerPreferences sets /ViewerPreferences /Direction so viewers lay out spreads in reading order
+func writeViewerPreferences(pdfFile, direction string) error {
+	fmt.Printf("Setting /ViewerPreferences /Direction %s on %s\n", viewerDirection(direction), pdfFile)
+
+	// In a real implementation, this would update the document catalog with pdfcpu
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestDominantScript(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"The quick brown fox", "LTR"},
+		{"בראשית ברא אלהים", "RTL"},
+		{"بسم الله الرحمن الرحيم", "RTL"},
+		{"Chapter 1: الفصل الأول من الكتاب", "RTL"},
+		{"The Arabic word كتاب means book", "LTR"},
+		{"12345 - 67", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := dominantScript(test.text)
+		if result != test.expected {
+			t.Errorf("Expected %q for %q, got %q", test.expected, test.text, result)
+		}
+	}
+}
+
+func TestViewerDirection(t *testing.T) {
+	for _, direction := range []string{"RTL", "LTR"} {
+		value := viewerDirection(direction)
+		if directionFromViewer(value) != direction {
+			t.Errorf("Expected %s to round-trip through %s, got %q", direction, value, directionFromViewer(value))
+		}
+	}
+	if directionFromViewer("") != "" {
+		t.Errorf("Expected no direction without a viewer preference, got %q", directionFromViewer(""))
+	}
+}
+
+func TestResolveDirection(t *testing.T) {
+	config := &BookletConfig{InputFile: "test.pdf", ReadingDirection: "LTR"}
+	if err := resolveDirection(config); err != nil || config.ReadingDirection != "LTR" {
+		t.Errorf("Expected an explicit direction to be kept, got %s (%v)", config.ReadingDirection, err)
+	}
+
+	config.ReadingDirection = DirectionAuto
+	if err := resolveDirection(config); err != nil || config.ReadingDirection != defaultDirection {
+		t.Errorf("Expected the default direction for an input with nothing to detect, got %s (%v)", config.ReadingDirection, err)
+	}
+}
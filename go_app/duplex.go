@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Print modes for the print-ready output
+const (
+	DuplexManual = "manual" // Separate front and back files for manual duplex
+	DuplexLong   = "long"   // One interleaved file for a printer that flips on the long edge
+	DuplexShort  = "short"  // One interleaved file for a printer that flips on the short edge
+)
+
+// Grouping of the interleaved duplex output
+const (
+	DuplexPerSignature = "signature" // One file per signature
+	DuplexPerBook      = "book"      // One file for the whole book
+)
+
+// Output tray face for manual duplex
+const (
+	FaceUp   = "up"   // Sheets come out printed side up, the back stack is reversed
+	FaceDown = "down" // Sheets come out printed side down, the back stack keeps its order
+)
+
+// printReadyDir is the folder the print-ready files are written to
+const printReadyDir = "print_ready"
+
+var (
+	validDuplexModes  = []string{DuplexManual, DuplexLong, DuplexShort}
+	validDuplexScopes = []string{DuplexPerSignature, DuplexPerBook}
+	validFaces        = []string{FaceUp, FaceDown}
+)
+
+// PrintOptions controls how the print-ready files are laid out
+type PrintOptions struct {
+	Mode        string  // "manual", "long" or "short"
+	Scope       string  // "signature" or "book"
+	Face        string  // "up" or "down"
+	BackOffsetX float64 // Back side correction in mm
+	BackOffsetY float64
+	BackScale   float64 // Back side scale, 0 or 1 for none
+	Binding     string  // "left", "right" or "top" binding edge
+	CutStack    bool    // Assign the n-up cells so each cuts into a contiguous stack
+	Repeat      bool    // Repeat every booklet side in all n-up cells
+	Perfect     bool    // Every cell holds a single perfect-bound page, not a spread
+	Numerals    string  // Numeral system of the stamped stack labels
+}
+
+// PrintSide is one side of a physical sheet in a print-ready file
+type PrintSide struct {
+	Sheet    int     // Sheet number within the signature, starting at 1
+	Back     bool    // True for the back side of the sheet
+	Pages    []int   // Booklet pages placed on this side by the n-up layout
+	Rotation int     // Rotation applied to the side before printing
+	ShiftX   float64 // Content shift in points to register the back with the front
+	ShiftY   float64
+	Scale    float64 // Content scale, 1 for none
+}
+
+// PrintFile is a print-ready PDF and the sheet sides it contains
+type PrintFile struct {
+	Name  string
+	Sides []PrintSide
+}
+
+// isLandscapeSheet reports whether the imposed sheet is landscape for the layout
+func isLandscapeSheet(pagesPerSheet int) bool {
+	// Booklet spreads are landscape; every doubling of the n-up turns the sheet
+	landscape := true
+	for n := pagesPerSheet; n > 1; n /= 2 {
+		landscape = !landscape
+	}
+	return landscape
+}
+
+// backRotation returns the rotation for back sides so they register with the fronts
+//
+// The reader turns the leaves about the fold, vertical for side binding and
+// horizontal for top binding. A printer flipping the sheet about the other
+// axis turns the back upside down. A hand-fed stack goes back over the edges
+// bookit.sh turned it on: the short edge, or the long edge of 8-up sheets.
+// Top binding turns the sheet with the fold, so it keeps the side binding's turns.
+func backRotation(mode string, pagesPerSheet int, binding string) int {
+	landscape := sheetIsLandscape(pagesPerSheet, binding)
+	var flipsVertical bool
+	switch mode {
+	case DuplexLong:
+		// The long edge of a portrait sheet is vertical
+		flipsVertical = !landscape
+	case DuplexShort:
+		flipsVertical = landscape
+	default:
+		flipsVertical = landscape != (pagesPerSheet == 8)
+	}
+	if flipsVertical != foldIsVertical(binding) {
+		return 180
+	}
+	return 0
+}
+
+// printBackRotation returns the back rotation of the print-ready files
+func printBackRotation(pagesPerSheet int, opts PrintOptions) int {
+	if opts.Perfect {
+		return perfectBackRotation(opts.Mode, pagesPerSheet, opts.Binding)
+	}
+	return backRotation(opts.Mode, pagesPerSheet, opts.Binding)
+}
+
+// signatureSheets groups the booklet pages of one signature into physical sheets
+//
+// Booklet pages alternate front and back; the n-up layout then places
+// pagesPerSheet fronts (or backs) on each physical side.
+func signatureSheets(firstPage, sides, pagesPerSheet int) ([]PrintSide, []PrintSide) {
+	var fronts, backs []PrintSide
+	for i := 0; i < sides; i += 2 * pagesPerSheet {
+		sheet := len(fronts) + 1
+		front := PrintSide{Sheet: sheet}
+		back := PrintSide{Sheet: sheet, Back: true}
+		for j := i; j < i+2*pagesPerSheet && j < sides; j += 2 {
+			front.Pages = append(front.Pages, firstPage+j)
+			if j+1 < sides {
+				back.Pages = append(back.Pages, firstPage+j+1)
+			}
+		}
+		fronts = append(fronts, front)
+		backs = append(backs, back)
+	}
+	return fronts, backs
+}
+
+// planPrintFiles lays out the print-ready files for the chosen duplex mode
+func planPrintFiles(outputFile string, totalSides, sidesPerSignature, pagesPerSheet int, opts PrintOptions) []PrintFile {
+	base := filepath.Base(outputFile)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	rotation := printBackRotation(pagesPerSheet, opts)
+	shiftX, shiftY := backCorrection(opts.BackOffsetX, opts.BackOffsetY, rotation)
+	scale := opts.BackScale
+	if scale == 0 {
+		scale = 1
+	}
+
+	var files []PrintFile
+	var book PrintFile
+	if sidesPerSignature <= 0 {
+		sidesPerSignature = totalSides
+	}
+	sheetsFor := signatureSheets
+	switch {
+	case opts.CutStack:
+		sheetsFor = cutStackSheets
+		if opts.Mode != DuplexManual && opts.Mode != "" && opts.Scope == DuplexPerBook {
+			// One file is cut as one pile, so the stacks run across the whole book
+			sidesPerSignature = totalSides
+		}
+	case opts.Repeat:
+		sheetsFor = repeatSheets
+	}
+
+	ind := 0
+	for first := 1; first <= totalSides; first += sidesPerSignature {
+		ind++
+		sides := sidesPerSignature
+		if first+sides-1 > totalSides {
+			sides = totalSides - first + 1
+		}
+		fronts, backs := sheetsFor(first, sides, pagesPerSheet)
+		for i := range fronts {
+			fronts[i].Scale = 1
+			backs[i].Rotation = rotation
+			backs[i].ShiftX, backs[i].ShiftY = shiftX, shiftY
+			backs[i].Scale = scale
+		}
+		signatureName := fmt.Sprintf("%s_%d.pdf", name, ind)
+
+		if opts.Mode == DuplexManual || opts.Mode == "" {
+			if opts.Face != FaceDown {
+				// Reverse the back stack so it can be fed again after flipping
+				for i, j := 0, len(backs)-1; i < j; i, j = i+1, j-1 {
+					backs[i], backs[j] = backs[j], backs[i]
+				}
+			}
+			files = append(files,
+				PrintFile{Name: fmt.Sprintf("%d_F_%s", ind, signatureName), Sides: fronts},
+				PrintFile{Name: fmt.Sprintf("%d_B_%s", ind, signatureName), Sides: backs})
+			continue
+		}
+
+		interleaved := make([]PrintSide, 0, 2*len(fronts))
+		for i := range fronts {
+			interleaved = append(interleaved, fronts[i], backs[i])
+		}
+		if opts.Scope == DuplexPerBook {
+			book.Sides = append(book.Sides, interleaved...)
+			continue
+		}
+		files = append(files, PrintFile{Name: fmt.Sprintf("%d_D_%s", ind, signatureName), Sides: interleaved})
+	}
+
+	if len(book.Sides) > 0 {
+		book.Name = fmt.Sprintf("D_%s.pdf", name)
+		files = append(files, book)
+	}
+	return files
+}
+
+// generatePrintPages writes the print-ready front/back or duplex files
+func generatePrintPages(outputFile string, totalSides, sidesPerSignature, pagesPerSheet int, opts PrintOptions) []PrintFile {
+	files := planPrintFiles(outputFile, totalSides, sidesPerSignature, pagesPerSheet, opts)
+
+	fmt.Printf("Generating print-ready files in %s (mode=%s", printReadyDir, opts.Mode)
+	if opts.Mode == DuplexManual {
+		fmt.Printf(", face=%s)\n", opts.Face)
+	} else {
+		fmt.Printf(", scope=%s)\n", opts.Scope)
+	}
+
+	rotation := printBackRotation(pagesPerSheet, opts)
+	fmt.Printf("  Back sides rotated by %d degrees\n", rotation)
+	if rotation != 0 && opts.Mode != DuplexManual {
+		fmt.Printf("  Flipping on the %s edge would print them without the turn\n", uprightFlipEdge(printLayout(pagesPerSheet, opts), opts.Binding))
+	}
+	if opts.BackOffsetX != 0 || opts.BackOffsetY != 0 {
+		fmt.Printf("  Back sides shifted by %.2f,%.2f mm\n", opts.BackOffsetX, opts.BackOffsetY)
+	}
+	if opts.BackScale != 0 && opts.BackScale != 1 {
+		fmt.Printf("  Back sides scaled by %.4f\n", opts.BackScale)
+	}
+	for _, file := range files {
+		fmt.Printf("  %s: %d sides\n", filepath.Join(printReadyDir, file.Name), len(file.Sides))
+	}
+	switch {
+	case opts.CutStack:
+		addStackLabels(stackLabels(files), pagesPerSheet, opts.Perfect, opts.Numerals)
+	case opts.Repeat:
+		fmt.Printf("  Every side repeated in all %d cells: each cut stack is a whole copy\n", pagesPerSheet)
+	}
+
+	// In a real implementation, this would collect, n-up, rotate and shift the pages into each file
+	return files
+}
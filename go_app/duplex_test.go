@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackRotation(t *testing.T) {
+	testCases := []struct {
+		mode          string
+		pagesPerSheet int
+		binding       string
+		expected      int
+	}{
+		{DuplexManual, 1, BindingLeft, 0},
+		{DuplexManual, 2, BindingLeft, 180},
+		{DuplexManual, 4, BindingLeft, 0},
+		{DuplexManual, 8, BindingLeft, 0},
+		{DuplexManual, 2, BindingRight, 180},
+		{DuplexManual, 1, BindingTop, 0},
+		{DuplexManual, 2, BindingTop, 180},
+		{DuplexManual, 4, BindingTop, 0},
+		{DuplexManual, 8, BindingTop, 0},
+		{DuplexLong, 1, BindingLeft, 180},
+		{DuplexLong, 2, BindingLeft, 0},
+		{DuplexLong, 4, BindingLeft, 180},
+		{DuplexLong, 2, BindingTop, 0},
+		{DuplexShort, 1, BindingLeft, 0},
+		{DuplexShort, 2, BindingLeft, 180},
+		{DuplexShort, 8, BindingLeft, 180},
+		{DuplexShort, 8, BindingTop, 180},
+	}
+
+	for _, tc := range testCases {
+		rotation := backRotation(tc.mode, tc.pagesPerSheet, tc.binding)
+		if rotation != tc.expected {
+			t.Errorf("For mode=%s, PagesPerSheet=%d, binding=%s, expected rotation %d, got %d",
+				tc.mode, tc.pagesPerSheet, tc.binding, tc.expected, rotation)
+		}
+	}
+}
+
+func TestSignatureSheets(t *testing.T) {
+	fronts, backs := signatureSheets(1, 8, 2)
+	if len(fronts) != 2 || len(backs) != 2 {
+		t.Fatalf("Expected 2 physical sheets, got %d fronts and %d backs", len(fronts), len(backs))
+	}
+	if !reflect.DeepEqual(fronts[0].Pages, []int{1, 3}) || !reflect.DeepEqual(backs[1].Pages, []int{6, 8}) {
+		t.Errorf("Unexpected page grouping: fronts %+v, backs %+v", fronts, backs)
+	}
+}
+
+func TestPlanPrintFilesManual(t *testing.T) {
+	files := planPrintFiles("out/booklet.pdf", 8, 4, 1, PrintOptions{Mode: DuplexManual, Face: FaceUp})
+	if len(files) != 4 {
+		t.Fatalf("Expected front and back files for 2 signatures, got %d files", len(files))
+	}
+	if files[0].Name != "1_F_booklet_1.pdf" || files[1].Name != "1_B_booklet_1.pdf" {
+		t.Errorf("Unexpected file names: %s, %s", files[0].Name, files[1].Name)
+	}
+	// Face up reverses the back stack
+	if files[1].Sides[0].Sheet != 2 || files[1].Sides[0].Pages[0] != 4 {
+		t.Errorf("Expected reversed back stack starting with sheet 2, got %+v", files[1].Sides)
+	}
+
+	files = planPrintFiles("booklet.pdf", 8, 4, 1, PrintOptions{Mode: DuplexManual, Face: FaceDown})
+	if files[1].Sides[0].Sheet != 1 {
+		t.Errorf("Face down should keep the back stack in order, got %+v", files[1].Sides)
+	}
+}
+
+func TestPlanPrintFilesDuplex(t *testing.T) {
+	files := planPrintFiles("booklet.pdf", 8, 4, 1, PrintOptions{Mode: DuplexLong, Scope: DuplexPerSignature})
+	if len(files) != 2 || files[0].Name != "1_D_booklet_1.pdf" {
+		t.Fatalf("Expected one interleaved file per signature, got %+v", files)
+	}
+
+	sides := files[0].Sides
+	if len(sides) != 4 || sides[0].Back || !sides[1].Back || sides[1].Sheet != 1 {
+		t.Errorf("Expected front/back interleaving, got %+v", sides)
+	}
+	if sides[1].Rotation != 180 || sides[0].Rotation != 0 {
+		t.Errorf("Expected only back sides rotated for long-edge 1-up, got %+v", sides)
+	}
+
+	files = planPrintFiles("booklet.pdf", 8, 4, 1, PrintOptions{Mode: DuplexShort, Scope: DuplexPerBook})
+	if len(files) != 1 || files[0].Name != "D_booklet.pdf" || len(files[0].Sides) != 8 {
+		t.Errorf("Expected a single interleaved file for the book, got %+v", files)
+	}
+	if files[0].Sides[1].Rotation != 0 {
+		t.Errorf("Short-edge 1-up backs should not be rotated, got %d", files[0].Sides[1].Rotation)
+	}
+}
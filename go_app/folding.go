@@ -0,0 +1,138 @@
+package main
+
+import "fmt"
+
+// FoldLine is the spine of a folded sheet as it lies flat, a vertical segment on the front side
+//
+// Positions are in points from the lower left corner of the template sheet.
+type FoldLine struct {
+	X      float64
+	Bottom float64
+	Top    float64 // Head of the folded pages, where the head-to-head fold meets the spine
+}
+
+// quartoTemplate is an 8-page sheet folded twice, the upper row head to head with the lower
+func quartoTemplate() ImpositionTemplate {
+	slots := func(pages []int, rotate int) []TemplateSlot {
+		row := make([]TemplateSlot, len(pages))
+		for i, page := range pages {
+			row[i] = TemplateSlot{Page: fmt.Sprintf("8*s+%d", page), Rotate: rotate}
+		}
+		return row
+	}
+	return ImpositionTemplate{
+		Name:            "quarto",
+		Columns:         2,
+		Rows:            2,
+		Flip:            FlipTurn,
+		SignatureSheets: 1,
+		Folded:          true,
+		Front:           append(slots([]int{5, 4}, 180), slots([]int{8, 1}, 0)...),
+		Back:            append(slots([]int{3, 6}, 180), slots([]int{2, 7}, 0)...),
+	}
+}
+
+// templateSheetSize returns the sheet turned so the template slots are portrait, and the slot size
+func templateSheetSize(t ImpositionTemplate, sheetWidth, sheetHeight float64) (float64, float64, float64, float64) {
+	landscape := t.Columns > t.Rows
+	width, height := orientSheet(sheetWidth, sheetHeight, landscape)
+	return width, height, width / float64(t.Columns), height / float64(t.Rows)
+}
+
+// spineSlot returns the front slot of the first page, right of the spine of a folded sheet
+//
+// The first and last page of a folded signature are conjugate: they sit side
+// by side on the front, the last page left of the first, and the last fold
+// runs between them.
+func spineSlot(t ImpositionTemplate) (int, error) {
+	n := t.SheetPages()
+	if t.SignatureSheets > 1 {
+		n *= t.SignatureSheets
+	}
+	front, _, err := sheetPages(t, n, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	first, last := -1, -1
+	for i, page := range front {
+		switch page {
+		case 1:
+			first = i
+		case n:
+			last = i
+		}
+	}
+	if first < 0 || last < 0 || first/t.Columns != last/t.Columns || first != last+1 {
+		return 0, fmt.Errorf("pages 1 and %d must sit side by side on the front of a folded sheet, page 1 on the right", n)
+	}
+	return first, nil
+}
+
+// finalFold returns the spine of a folded template sheet lying flat
+func finalFold(t ImpositionTemplate, sheetWidth, sheetHeight float64) (FoldLine, error) {
+	first, err := spineSlot(t)
+	if err != nil {
+		return FoldLine{}, err
+	}
+
+	_, height, slotWidth, slotHeight := templateSheetSize(t, sheetWidth, sheetHeight)
+	row := first / t.Columns
+	bottom := height - float64(row+1)*slotHeight
+	return FoldLine{
+		X:      float64(first%t.Columns) * slotWidth,
+		Bottom: bottom,
+		Top:    bottom + slotHeight,
+	}, nil
+}
+
+// foldStations places the sewing stations on the spine of a folded sheet, measured from the head
+func foldStations(fold FoldLine, pagesPerSheet int) [][2]float64 {
+	percentages := stationPercentages(pagesPerSheet)
+	stations := make([][2]float64, len(percentages))
+	for i, percent := range percentages {
+		stations[i] = [2]float64{fold.X, fold.Top - percent/100*(fold.Top-fold.Bottom)}
+	}
+	return stations
+}
+
+// foldCollationMarks steps one collation block per signature down the spine of its folded sheet
+//
+// Each block is drawn on the front of the signature's first sheet, which is
+// the outside of the folded gathering.
+func foldCollationMarks(fold FoldLine, signatures, sidesPerSignature int) []CollationMark {
+	length := fold.Top - fold.Bottom
+	marks := collationMarks(signatures, sidesPerSignature, 1, 2*collationBlockWidth*mmToPoints, length, BindingLeft)
+	for i := range marks {
+		marks[i].X += fold.X - collationBlockWidth*mmToPoints
+		marks[i].Y += fold.Bottom
+	}
+	return marks
+}
+
+// addFoldMarks stamps the stations and collation blocks on the final fold of every folded sheet
+func addFoldMarks(pdfFile string, t ImpositionTemplate, sheetWidth, sheetHeight float64, sheets, sheetsPerSignature int, stations, collation bool) error {
+	fold, err := finalFold(t, sheetWidth, sheetHeight)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Marking the final fold of %s at x=%.1f, %.1f-%.1f points\n", pdfFile, fold.X, fold.Bottom, fold.Top)
+
+	if stations {
+		points := foldStations(fold, t.Columns*t.Rows/2)
+		fmt.Printf("  %d stations on the front of every sheet:", len(points))
+		for _, point := range points {
+			fmt.Printf(" %.1f", point[1])
+		}
+		fmt.Println()
+	}
+	if collation && sheetsPerSignature > 0 {
+		signatures := (sheets + sheetsPerSignature - 1) / sheetsPerSignature
+		for _, mark := range foldCollationMarks(fold, signatures, 2*sheetsPerSignature) {
+			fmt.Printf("  Signature %02d: block on page %d at %.1f points from the foot of the sheet\n", mark.Signature, mark.Side, mark.Y)
+		}
+	}
+
+	// In a real implementation, this would stamp the dots and draw the blocks on the front sides
+	return nil
+}
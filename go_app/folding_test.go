@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestQuartoTemplate(t *testing.T) {
+	quarto := builtinImpositions["quarto"]
+	if err := validateImposition(quarto); err != nil {
+		t.Fatalf("Expected the quarto to be valid, got: %v", err)
+	}
+
+	sheets, err := imposeTemplate(quarto, []int{1, 2, 3, 4, 5, 6, 7, 8}, 1, "LTR")
+	if err != nil || len(sheets) != 1 {
+		t.Fatalf("Expected one sheet, got %d (%v)", len(sheets), err)
+	}
+	if front := describeImposedSide(quarto, sheets[0].Front); front != "5^ 4^ / 8 1" {
+		t.Errorf("Expected the outer forme 5^ 4^ / 8 1, got %s", front)
+	}
+	if back := describeImposedSide(quarto, sheets[0].Back); back != "3^ 6^ / 2 7" {
+		t.Errorf("Expected the inner forme 3^ 6^ / 2 7, got %s", back)
+	}
+}
+
+func TestFinalFold(t *testing.T) {
+	tests := []struct {
+		name      string
+		expectedX float64
+		expectedH float64
+	}{
+		{"quarto", a4Width / 2, a4Height / 2},
+		{"octavo", a4Height * 3 / 4, a4Width / 2},
+	}
+
+	for _, test := range tests {
+		fold, err := finalFold(builtinImpositions[test.name], a4Width, a4Height)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if math.Abs(fold.X-test.expectedX) > 0.01 || fold.Bottom != 0 || math.Abs(fold.Top-test.expectedH) > 0.01 {
+			t.Errorf("%s: expected the spine at x=%.1f from 0 to %.1f, got %+v", test.name, test.expectedX, test.expectedH, fold)
+		}
+	}
+}
+
+func TestFoldedTemplateNeedsSpine(t *testing.T) {
+	template := ImpositionTemplate{
+		Columns: 3, Rows: 2, Flip: FlipTurn, SignatureSheets: 1, Folded: true,
+		Front: []TemplateSlot{{Page: "1"}, {Page: "3"}, {Page: "5"}, {Page: "7"}, {Page: "9"}, {Page: "11"}},
+		Back:  []TemplateSlot{{Page: "6"}, {Page: "4"}, {Page: "2"}, {Page: "12"}, {Page: "10"}, {Page: "8"}},
+	}
+	if err := validateImposition(template); err == nil || !strings.Contains(err.Error(), "side by side") {
+		t.Errorf("Expected error about the spine of a folded sheet, got: %v", err)
+	}
+
+	template.Folded = false
+	if err := validateImposition(template); err != nil {
+		t.Errorf("Expected the unfolded template to be valid, got: %v", err)
+	}
+}
+
+func TestFoldStationsAndMarks(t *testing.T) {
+	fold, _ := finalFold(builtinImpositions["quarto"], a4Width, a4Height)
+
+	stations := foldStations(fold, 2)
+	if len(stations) != 6 {
+		t.Fatalf("Expected 6 stations, got %d", len(stations))
+	}
+	for i, station := range stations {
+		if station[0] != fold.X || station[1] <= fold.Bottom || station[1] >= fold.Top {
+			t.Errorf("Expected station %d on the spine, got %v", i+1, station)
+		}
+		if i > 0 && station[1] >= stations[i-1][1] {
+			t.Errorf("Expected station %d below station %d", i+1, i)
+		}
+	}
+
+	marks := foldCollationMarks(fold, 3, 2)
+	if len(marks) != 3 {
+		t.Fatalf("Expected 3 marks, got %d", len(marks))
+	}
+	for i, mark := range marks {
+		if math.Abs(mark.X+mark.Width/2-fold.X) > 0.01 {
+			t.Errorf("Expected mark %d centered on the spine, got x=%.1f", i+1, mark.X)
+		}
+		if mark.Y < fold.Bottom || mark.Y+mark.Height > fold.Top+0.01 {
+			t.Errorf("Expected mark %d within the spine, got y=%.1f", i+1, mark.Y)
+		}
+		if mark.Side != 2*i+1 {
+			t.Errorf("Expected mark %d on the front of sheet %d, got page %d", i+1, i+1, mark.Side)
+		}
+	}
+}
@@ -2,7 +2,10 @@ module booklet-maker
 
 go 1.25
 
-require github.com/pdfcpu/pdfcpu v0.11.1
+require (
+	github.com/pdfcpu/pdfcpu v0.11.1
+	gopkg.in/yaml.v2 v2.4.0
+)
 
 require (
 	github.com/clipperhouse/uax29/v2 v2.2.0 // indirect
@@ -14,5 +17,4 @@ require (
 	golang.org/x/crypto v0.43.0 // indirect
 	golang.org/x/image v0.32.0 // indirect
 	golang.org/x/text v0.30.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RunningHead is the text stamped in the top margin of a prepared page
+type RunningHead struct {
+	Page     int // Page in the prepared (reading order) PDF
+	Text     string
+	Position string // pdfcpu anchor, "tl" or "tr", on the outer side of the page
+}
+
+// readHeadsFile reads chapter titles from a sidecar file
+//
+// Every line holds the source page a chapter starts on and its title, e.g.
+// "17 The Second Voyage". Empty lines and lines starting with # are ignored.
+func readHeadsFile(path string) ([]Bookmark, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var chapters []Bookmark
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		page, title, _ := strings.Cut(text, " ")
+		number, err := strconv.Atoi(page)
+		if err != nil || number < 1 {
+			return nil, fmt.Errorf("%s:%d: expected a page number, got %q", path, line, page)
+		}
+		chapters = append(chapters, Bookmark{Title: strings.TrimSpace(title), Page: number, Level: 1})
+	}
+	return chapters, scanner.Err()
+}
+
+// headsTitle returns the book title for the verso heads, the input file name if none is set
+func headsTitle(title, inputFile string) string {
+	if title != "" {
+		return title
+	}
+	base := filepath.Base(inputFile)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// runningHeads puts the book title on versos and the current chapter on rectos
+//
+// Blank and chapter-opening pages get no head, nor do rectos before the first
+// chapter. Heads sit on the outer side of the page, which is mirrored for RTL
+// books.
+func runningHeads(order []int, chapters []Bookmark, title, direction string) []RunningHead {
+	versoPosition, rectoPosition := "tl", "tr"
+	if direction == "RTL" {
+		versoPosition, rectoPosition = "tr", "tl"
+	}
+
+	opening := make(map[int]bool, len(chapters))
+	for _, chapter := range chapters {
+		if chapter.Level == 1 {
+			opening[chapter.Page] = true
+		}
+	}
+
+	var heads []RunningHead
+	for i, source := range order {
+		if source == 0 || opening[source] {
+			continue
+		}
+		position := i + 1
+
+		if !isRecto(position) {
+			heads = append(heads, RunningHead{Page: position, Text: title, Position: versoPosition})
+			continue
+		}
+		chapter := ""
+		for _, bookmark := range chapters {
+			if bookmark.Level == 1 && bookmark.Page <= source {
+				chapter = bookmark.Title
+			}
+		}
+		if chapter != "" {
+			heads = append(heads, RunningHead{Page: position, Text: chapter, Position: rectoPosition})
+		}
+	}
+	return heads
+}
+
+// loadHeadsChapters reads the chapter titles from the sidecar file, or from the outline without one
+func loadHeadsChapters(config *BookletConfig, outline []Bookmark) ([]Bookmark, error) {
+	if config.HeadsFile != "" {
+		chapters, err := readHeadsFile(config.HeadsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read heads file: %w", err)
+		}
+		return chapters, nil
+	}
+	if outline != nil {
+		return outline, nil
+	}
+	outline, err := readOutline(config.InputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outline: %w", err)
+	}
+	return outline, nil
+}
+
+// stampRunningHeads stamps the running heads in the top margin of the prepared PDF
+func stampRunningHeads(pdfFile string, heads []RunningHead) error {
+	fmt.Printf("Adding running heads to %s, %d pages\n", pdfFile, len(heads))
+	for _, head := range heads {
+		fmt.Printf("  Page %d (%s): %q\n", head.Page, head.Position, head.Text)
+	}
+
+	// In a real implementation, this would stamp each head with "pos:<Position>, ma:10 10, offset:0 -18"
+	return nil
+}
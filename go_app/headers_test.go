@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunningHeads(t *testing.T) {
+	chapters := []Bookmark{
+		{Title: "One", Page: 3, Level: 1},
+		{Title: "One.1", Page: 4, Level: 2},
+		{Title: "Two", Page: 6, Level: 1},
+	}
+	order := []int{1, 2, 3, 4, 5, 0, 6, 7, 8}
+
+	heads := runningHeads(order, chapters, "Book", "LTR")
+	expected := []RunningHead{
+		{Page: 2, Text: "Book", Position: "tl"},
+		{Page: 4, Text: "Book", Position: "tl"},
+		{Page: 5, Text: "One", Position: "tr"},
+		{Page: 8, Text: "Book", Position: "tl"},
+		{Page: 9, Text: "Two", Position: "tr"},
+	}
+	if !reflect.DeepEqual(heads, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, heads)
+	}
+
+	heads = runningHeads(order, chapters, "Book", "RTL")
+	if heads[0].Position != "tr" || heads[2].Position != "tl" {
+		t.Errorf("Expected mirrored positions for RTL, got %+v", heads)
+	}
+}
+
+func TestReadHeadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heads.txt")
+	content := "# Chapters\n5 The First Voyage\n\n17 The Second Voyage\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chapters, err := readHeadsFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Bookmark{
+		{Title: "The First Voyage", Page: 5, Level: 1},
+		{Title: "The Second Voyage", Page: 17, Level: 1},
+	}
+	if !reflect.DeepEqual(chapters, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, chapters)
+	}
+
+	if err := os.WriteFile(path, []byte("five The First Voyage\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readHeadsFile(path); err == nil {
+		t.Error("Expected error for a line without a page number, got nil")
+	}
+}
+
+func TestHeadsTitle(t *testing.T) {
+	if title := headsTitle("", "scans/old_book.pdf"); title != "old_book" {
+		t.Errorf("Expected the file name as title, got %q", title)
+	}
+	if title := headsTitle("Voyages", "scans/old_book.pdf"); title != "Voyages" {
+		t.Errorf("Expected the given title, got %q", title)
+	}
+}
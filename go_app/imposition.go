@@ -0,0 +1,467 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// How the back of a template sheet lies behind its front
+const (
+	FlipTurn   = "turn"   // Turned over left to right, the back columns mirror the front
+	FlipTumble = "tumble" // Tumbled head over foot, the back rows mirror the front
+)
+
+var validFlips = []string{FlipTurn, FlipTumble}
+
+// TemplateSlot is one cell of a template sheet side
+//
+// Page is a formula of the page in the signature, counting from 1. It may use
+// n, the pages in the signature, s, the sheet within the signature counting
+// from 0, integers, + - * / and parentheses.
+type TemplateSlot struct {
+	Page   string `json:"page" yaml:"page"`
+	Rotate int    `json:"rotate,omitempty" yaml:"rotate,omitempty"` // 0, 90, 180 or 270, e.g. 180 for head-to-head
+}
+
+// ImpositionTemplate describes an imposition scheme: the sheet grid and the page in every slot
+//
+// Front and Back list the slots row by row from the top left, each side as
+// seen when it faces up.
+type ImpositionTemplate struct {
+	Name            string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Columns         int            `json:"columns" yaml:"columns"`
+	Rows            int            `json:"rows" yaml:"rows"`
+	Flip            string         `json:"flip" yaml:"flip"`                                             // "turn" or "tumble"
+	SignatureSheets int            `json:"signature_sheets,omitempty" yaml:"signature_sheets,omitempty"` // Sheets per signature, 0 to follow -sections
+	Folded          bool           `json:"folded,omitempty" yaml:"folded,omitempty"`                     // Folded into a signature without cutting
+	Front           []TemplateSlot `json:"front" yaml:"front"`
+	Back            []TemplateSlot `json:"back" yaml:"back"`
+}
+
+// ImposedSlot is a prepared page placed on a template sheet, 0 for a blank
+type ImposedSlot struct {
+	Page   int
+	Rotate int
+}
+
+// ImposedSheet is one physical sheet laid out by a template
+type ImposedSheet struct {
+	Signature int
+	Sheet     int // Sheet within the signature, starting at 1
+	Front     []ImposedSlot
+	Back      []ImposedSlot
+}
+
+// SheetPages returns the number of pages on both sides of a template sheet
+func (t ImpositionTemplate) SheetPages() int {
+	return 2 * t.Columns * t.Rows
+}
+
+// builtinImpositions are the templates of the -pages layouts and the folded quarto and octavo
+var builtinImpositions = map[string]ImpositionTemplate{
+	"booklet": nupTemplate("booklet", 1, 2, 1),
+	"2-up":    nupTemplate("2-up", 2, 2, 2),
+	"4-up":    nupTemplate("4-up", 4, 4, 2),
+	"8-up":    nupTemplate("8-up", 8, 4, 4),
+	"quarto":  quartoTemplate(),
+	"octavo":  octavoTemplate(),
+}
+
+// nupTemplate builds the template createBooklet and the n-up print pages produce today
+//
+// Every cell holds one folio of a nested signature, the outer folio first;
+// the spread on its back is moved to the mirrored cell of the row.
+func nupTemplate(name string, pagesPerSheet, columns, rows int) ImpositionTemplate {
+	t := ImpositionTemplate{Name: name, Columns: columns, Rows: rows, Flip: FlipTurn}
+	t.Front = make([]TemplateSlot, columns*rows)
+	t.Back = make([]TemplateSlot, columns*rows)
+	cellsPerRow := columns / 2
+	for cell := 0; cell < pagesPerSheet; cell++ {
+		folio := fmt.Sprintf("(%d*s+%d)", pagesPerSheet, cell)
+		row, column := cell/cellsPerRow, 2*(cell%cellsPerRow)
+		front := row*columns + column
+		back := row*columns + columns - 2 - column
+		t.Front[front] = TemplateSlot{Page: "n-2*" + folio}
+		t.Front[front+1] = TemplateSlot{Page: "2*" + folio + "+1"}
+		t.Back[back] = TemplateSlot{Page: "2*" + folio + "+2"}
+		t.Back[back+1] = TemplateSlot{Page: "n-2*" + folio + "-1"}
+	}
+	return t
+}
+
+// octavoTemplate is a 16-page sheet folded three times, the upper row head to head with the lower
+func octavoTemplate() ImpositionTemplate {
+	slots := func(pages []int, rotate int) []TemplateSlot {
+		row := make([]TemplateSlot, len(pages))
+		for i, page := range pages {
+			row[i] = TemplateSlot{Page: fmt.Sprintf("16*s+%d", page), Rotate: rotate}
+		}
+		return row
+	}
+	return ImpositionTemplate{
+		Name:            "octavo",
+		Columns:         4,
+		Rows:            2,
+		Flip:            FlipTurn,
+		SignatureSheets: 1,
+		Folded:          true,
+		Front:           append(slots([]int{5, 12, 9, 8}, 180), slots([]int{4, 13, 16, 1}, 0)...),
+		Back:            append(slots([]int{7, 10, 11, 6}, 180), slots([]int{2, 15, 14, 3}, 0)...),
+	}
+}
+
+// loadImposition returns a template from the configuration file, a JSON or YAML file, or a built-in one
+func loadImposition(configFile, name string) (ImpositionTemplate, error) {
+	var template ImpositionTemplate
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json", ".yaml", ".yml":
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return template, err
+		}
+		if ext == ".json" {
+			err = json.Unmarshal(data, &template)
+		} else {
+			err = yaml.Unmarshal(data, &template)
+		}
+		if err != nil {
+			return template, fmt.Errorf("invalid imposition template %s: %w", name, err)
+		}
+	default:
+		config, err := loadConfig(configFile)
+		if err != nil {
+			return template, err
+		}
+		var ok bool
+		if template, ok = config.Impositions[name]; !ok {
+			if template, ok = builtinImpositions[name]; !ok {
+				return template, fmt.Errorf("imposition %q not found in %s or the built-ins (%s)", name, configFile, strings.Join(builtinImpositionNames(), ", "))
+			}
+		}
+	}
+	if template.Name == "" {
+		template.Name = name
+	}
+
+	if err := validateImposition(template); err != nil {
+		return template, fmt.Errorf("imposition %s: %w", template.Name, err)
+	}
+	return template, nil
+}
+
+// builtinImpositionNames returns the names of the built-in templates in order
+func builtinImpositionNames() []string {
+	names := make([]string, 0, len(builtinImpositions))
+	for name := range builtinImpositions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateImposition checks the grid, the formulas and that every page is placed exactly once
+//
+// Signatures of one to three sheets are laid out, unless the template fixes
+// the sheets per signature. The pages either side of a leaf must follow each
+// other, which checks the flip between front and back. Folded sheets also
+// need their first and last page either side of the spine.
+func validateImposition(t ImpositionTemplate) error {
+	if t.Columns < 1 || t.Rows < 1 {
+		return fmt.Errorf("grid must have at least one column and row, got %dx%d", t.Columns, t.Rows)
+	}
+	if len(t.Front) != t.Columns*t.Rows || len(t.Back) != t.Columns*t.Rows {
+		return fmt.Errorf("front and back need %d slots each, got %d and %d", t.Columns*t.Rows, len(t.Front), len(t.Back))
+	}
+	if !isValidOption(t.Flip, validFlips) {
+		return fmt.Errorf("flip must be turn or tumble, got %s", t.Flip)
+	}
+	for _, slot := range append(append([]TemplateSlot(nil), t.Front...), t.Back...) {
+		if normalizeRotation(slot.Rotate)%90 != 0 {
+			return fmt.Errorf("rotation of page %s must be a multiple of 90, got %d", slot.Page, slot.Rotate)
+		}
+	}
+
+	sheetCounts := []int{1, 2, 3}
+	if t.SignatureSheets > 0 {
+		sheetCounts = []int{t.SignatureSheets}
+	}
+	for _, sheets := range sheetCounts {
+		n := sheets * t.SheetPages()
+		placed := make(map[int]string, n)
+		for s := 0; s < sheets; s++ {
+			front, back, err := sheetPages(t, n, s)
+			if err != nil {
+				return err
+			}
+			for side, pages := range [][]int{front, back} {
+				for i, page := range pages {
+					where := fmt.Sprintf("sheet %d %s slot %d", s+1, []string{"front", "back"}[side], i+1)
+					if page < 1 || page > n {
+						return fmt.Errorf("%s holds page %d, outside 1-%d", where, page, n)
+					}
+					if previous, ok := placed[page]; ok {
+						return fmt.Errorf("page %d of %d is placed twice, on %s and %s", page, n, previous, where)
+					}
+					placed[page] = where
+				}
+			}
+			for i, page := range front {
+				behind := back[behindSlot(t, i)]
+				low, high := page, behind
+				if low > high {
+					low, high = high, low
+				}
+				if low%2 != 1 || high != low+1 {
+					return fmt.Errorf("sheet %d front slot %d holds page %d but page %d is behind it", s+1, i+1, page, behind)
+				}
+			}
+		}
+	}
+
+	if t.Folded {
+		if _, err := spineSlot(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// behindSlot returns the back slot behind a front slot, as the flip lays the back
+func behindSlot(t ImpositionTemplate, slot int) int {
+	row, column := slot/t.Columns, slot%t.Columns
+	if t.Flip == FlipTumble {
+		row = t.Rows - 1 - row
+	} else {
+		column = t.Columns - 1 - column
+	}
+	return row*t.Columns + column
+}
+
+// sheetPages evaluates the page formulas of a sheet for a signature of n pages
+func sheetPages(t ImpositionTemplate, n, s int) ([]int, []int, error) {
+	vars := map[string]int{"n": n, "s": s}
+	evaluate := func(slots []TemplateSlot) ([]int, error) {
+		pages := make([]int, len(slots))
+		for i, slot := range slots {
+			page, err := evalFormula(slot.Page, vars)
+			if err != nil {
+				return nil, fmt.Errorf("page formula %q: %w", slot.Page, err)
+			}
+			pages[i] = page
+		}
+		return pages, nil
+	}
+
+	front, err := evaluate(t.Front)
+	if err != nil {
+		return nil, nil, err
+	}
+	back, err := evaluate(t.Back)
+	return front, back, err
+}
+
+// signatureSheetCount returns the sheets per signature of a template
+//
+// Templates without a fixed count hold as many pages per signature as the
+// -sections layout with the same number of folios per sheet side.
+func signatureSheetCount(t ImpositionTemplate, nsections int) int {
+	if t.SignatureSheets > 0 {
+		return t.SignatureSheets
+	}
+	pages := 2 * signaturePageCount(nsections, t.Columns*t.Rows/2)
+	sheets := (pages + t.SheetPages() - 1) / t.SheetPages()
+	if sheets < 1 {
+		sheets = 1
+	}
+	return sheets
+}
+
+// imposeTemplate lays the prepared pages out on template sheets
+//
+// RTL books are reversed first, as for createBooklet. A short last signature
+// is padded with blanks to whole sheets.
+func imposeTemplate(t ImpositionTemplate, order []int, sheetsPerSignature int, direction string) ([]ImposedSheet, error) {
+	pages := make([]int, len(order))
+	copy(pages, order)
+	if direction == "RTL" {
+		for i, j := 0, len(pages)-1; i < j; i, j = i+1, j-1 {
+			pages[i], pages[j] = pages[j], pages[i]
+		}
+	}
+
+	perSignature := sheetsPerSignature * t.SheetPages()
+	var sheets []ImposedSheet
+	for start, signature := 0, 1; start < len(pages); start, signature = start+perSignature, signature+1 {
+		count := len(pages) - start
+		if count > perSignature {
+			count = perSignature
+		}
+		sheetCount := (count + t.SheetPages() - 1) / t.SheetPages()
+		n := sheetCount * t.SheetPages()
+		page := func(number int) int {
+			if number < 1 || number > count {
+				return 0
+			}
+			return pages[start+number-1]
+		}
+
+		for s := 0; s < sheetCount; s++ {
+			front, back, err := sheetPages(t, n, s)
+			if err != nil {
+				return nil, err
+			}
+			sheet := ImposedSheet{Signature: signature, Sheet: s + 1}
+			for i, number := range front {
+				sheet.Front = append(sheet.Front, ImposedSlot{Page: page(number), Rotate: normalizeRotation(t.Front[i].Rotate)})
+			}
+			for i, number := range back {
+				sheet.Back = append(sheet.Back, ImposedSlot{Page: page(number), Rotate: normalizeRotation(t.Back[i].Rotate)})
+			}
+			sheets = append(sheets, sheet)
+		}
+	}
+	return sheets, nil
+}
+
+// writeImposedSheets writes the template sheets, front then back, to the booklet file
+func writeImposedSheets(inputFile, outputFile string, t ImpositionTemplate, sheets []ImposedSheet) error {
+	fmt.Printf("Imposing %s -> %s with template %s (%dx%d, %s), %d sheets\n",
+		inputFile, outputFile, t.Name, t.Columns, t.Rows, t.Flip, len(sheets))
+	for _, sheet := range sheets {
+		fmt.Printf("  Signature %d sheet %d: front %s, back %s\n",
+			sheet.Signature, sheet.Sheet, describeImposedSide(t, sheet.Front), describeImposedSide(t, sheet.Back))
+	}
+
+	// In a real implementation, this would place each page in its grid cell with pdfcpu
+	return nil
+}
+
+// describeImposedSide writes the rows of a sheet side, "/" between rows and "^" for turned pages
+func describeImposedSide(t ImpositionTemplate, slots []ImposedSlot) string {
+	var rows []string
+	for row := 0; row < t.Rows; row++ {
+		var cells []string
+		for _, slot := range slots[row*t.Columns : (row+1)*t.Columns] {
+			cell := strconv.Itoa(slot.Page)
+			if slot.Page == 0 {
+				cell = "-"
+			}
+			if slot.Rotate == 180 {
+				cell += "^"
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, strings.Join(cells, " "))
+	}
+	return strings.Join(rows, " / ")
+}
+
+// evalFormula evaluates an integer page formula with the given variables
+func evalFormula(expr string, vars map[string]int) (int, error) {
+	p := &formulaParser{input: strings.ReplaceAll(expr, " ", ""), vars: vars}
+	value, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q", p.input[p.pos:])
+	}
+	return value, nil
+}
+
+// formulaParser is a recursive descent parser for page formulas
+type formulaParser struct {
+	input string
+	pos   int
+	vars  map[string]int
+}
+
+// sum parses terms joined by + and -
+func (p *formulaParser) sum() (int, error) {
+	value, err := p.product()
+	for err == nil && p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+		op := p.input[p.pos]
+		p.pos++
+		var term int
+		term, err = p.product()
+		if op == '+' {
+			value += term
+		} else {
+			value -= term
+		}
+	}
+	return value, err
+}
+
+// product parses factors joined by * and /
+func (p *formulaParser) product() (int, error) {
+	value, err := p.factor()
+	for err == nil && p.pos < len(p.input) && (p.input[p.pos] == '*' || p.input[p.pos] == '/') {
+		op := p.input[p.pos]
+		p.pos++
+		var factor int
+		factor, err = p.factor()
+		switch {
+		case err != nil:
+		case op == '*':
+			value *= factor
+		case factor == 0:
+			err = fmt.Errorf("division by zero")
+		default:
+			value /= factor
+		}
+	}
+	return value, err
+}
+
+// factor parses a number, a variable, a negation or a parenthesized sum
+func (p *formulaParser) factor() (int, error) {
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of formula")
+	}
+	c := p.input[p.pos]
+	switch {
+	case c == '-':
+		p.pos++
+		value, err := p.factor()
+		return -value, err
+	case c == '(':
+		p.pos++
+		value, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return 0, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return value, nil
+	case c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+			p.pos++
+		}
+		return strconv.Atoi(p.input[start:p.pos])
+	default:
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= 'a' && p.input[p.pos] <= 'z') {
+			p.pos++
+		}
+		name := p.input[start:p.pos]
+		value, ok := p.vars[name]
+		if !ok {
+			if name == "" {
+				name = string(c)
+			}
+			return 0, fmt.Errorf("unknown %q", name)
+		}
+		return value, nil
+	}
+}
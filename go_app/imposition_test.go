@@ -0,0 +1,230 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvalFormula(t *testing.T) {
+	vars := map[string]int{"n": 32, "s": 3}
+	tests := []struct {
+		formula  string
+		expected int
+	}{
+		{"7", 7},
+		{"n", 32},
+		{"n-2*s", 26},
+		{"2*(4*s+1)+1", 27},
+		{"n - 2 * (2*s+1) - 1", 17},
+		{"n/2+s", 19},
+		{"-s+10", 7},
+	}
+
+	for _, test := range tests {
+		result, err := evalFormula(test.formula, vars)
+		if err != nil || result != test.expected {
+			t.Errorf("Expected %q to be %d, got %d (%v)", test.formula, test.expected, result, err)
+		}
+	}
+
+	for _, formula := range []string{"", "n+", "(n", "x*2", "n/0", "n%2", "2n"} {
+		if _, err := evalFormula(formula, vars); err == nil {
+			t.Errorf("Expected an error for %q", formula)
+		}
+	}
+}
+
+func TestBuiltinImpositionsValid(t *testing.T) {
+	for _, name := range builtinImpositionNames() {
+		if err := validateImposition(builtinImpositions[name]); err != nil {
+			t.Errorf("Expected built-in %s to be valid, got: %v", name, err)
+		}
+	}
+}
+
+func TestBuiltinImpositionsReproduceLayouts(t *testing.T) {
+	tests := []struct {
+		name          string
+		pagesPerSheet int
+	}{
+		{"booklet", 1},
+		{"2-up", 2},
+		{"4-up", 4},
+		{"8-up", 8},
+	}
+
+	for _, test := range tests {
+		template := builtinImpositions[test.name]
+		sidesPerSignature := signaturePageCount(8, test.pagesPerSheet)
+		order := make([]int, 4*sidesPerSignature) // Two full signatures
+		for i := range order {
+			order[i] = i + 1
+		}
+
+		sides := imposeBookletSides(order, sidesPerSignature, "LTR")
+		sheets, err := imposeTemplate(template, order, signatureSheetCount(template, 8), "LTR")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		// Every front cell holds the spread createBooklet makes, its back spread sits in the mirrored cell
+		var fronts, backs [][2]int
+		cellsPerRow := template.Columns / 2
+		for _, sheet := range sheets {
+			for cell := 0; cell < test.pagesPerSheet; cell++ {
+				row, column := cell/cellsPerRow, 2*(cell%cellsPerRow)
+				front := row*template.Columns + column
+				back := row*template.Columns + template.Columns - 2 - column
+				fronts = append(fronts, [2]int{sheet.Front[front].Page, sheet.Front[front+1].Page})
+				backs = append(backs, [2]int{sheet.Back[back].Page, sheet.Back[back+1].Page})
+			}
+		}
+		var expectedFronts, expectedBacks [][2]int
+		for i := 0; i+1 < len(sides); i += 2 {
+			expectedFronts = append(expectedFronts, sides[i])
+			expectedBacks = append(expectedBacks, sides[i+1])
+		}
+		if !reflect.DeepEqual(fronts, expectedFronts) || !reflect.DeepEqual(backs, expectedBacks) {
+			t.Errorf("%s: expected fronts %v and backs %v, got %v and %v", test.name, expectedFronts, expectedBacks, fronts, backs)
+		}
+	}
+}
+
+func TestImposeTemplateOctavo(t *testing.T) {
+	order := make([]int, 20)
+	for i := range order {
+		order[i] = i + 1
+	}
+	sheets, err := imposeTemplate(builtinImpositions["octavo"], order, 1, "LTR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sheets) != 2 || sheets[1].Signature != 2 {
+		t.Fatalf("Expected two one-sheet signatures, got %+v", sheets)
+	}
+
+	front := sheets[0].Front
+	if front[4].Page != 4 || front[7].Page != 1 || front[0].Page != 5 || front[0].Rotate != 180 || front[4].Rotate != 0 {
+		t.Errorf("Expected 5 head to head above 4 and page 1 bottom right, got %+v", front)
+	}
+	// The second signature holds pages 17-20 and twelve blanks
+	placed := 0
+	for _, slot := range append(sheets[1].Front, sheets[1].Back...) {
+		if slot.Page != 0 {
+			placed++
+		}
+	}
+	if placed != 4 || sheets[1].Front[7].Page != 17 {
+		t.Errorf("Expected pages 17-20 padded with blanks, got %+v", sheets[1])
+	}
+
+	if side := describeImposedSide(builtinImpositions["octavo"], front); side != "5^ 12^ 9^ 8^ / 4 13 16 1" {
+		t.Errorf("Unexpected octavo front: %s", side)
+	}
+}
+
+func TestValidateImpositionErrors(t *testing.T) {
+	valid := builtinImpositions["booklet"]
+
+	duplicate := valid
+	duplicate.Front = []TemplateSlot{{Page: "n-2*s"}, {Page: "n-2*s"}}
+
+	turned := valid
+	turned.Back = []TemplateSlot{valid.Back[1], valid.Back[0]}
+
+	tests := []struct {
+		name     string
+		template ImpositionTemplate
+		expected string
+	}{
+		{"grid", ImpositionTemplate{Columns: 0, Rows: 1, Flip: FlipTurn}, "at least one column"},
+		{"slots", ImpositionTemplate{Columns: 2, Rows: 2, Flip: FlipTurn, Front: valid.Front, Back: valid.Back}, "need 4 slots"},
+		{"flip", ImpositionTemplate{Columns: 2, Rows: 1, Flip: "spin", Front: valid.Front, Back: valid.Back}, "flip must be"},
+		{"duplicate", duplicate, "placed twice"},
+		{"leaf", turned, "is behind it"},
+		{"formula", ImpositionTemplate{Columns: 2, Rows: 1, Flip: FlipTurn, Front: []TemplateSlot{{Page: "x"}, {Page: "1"}}, Back: valid.Back}, "page formula"},
+		{"range", ImpositionTemplate{Columns: 2, Rows: 1, Flip: FlipTurn, Front: []TemplateSlot{{Page: "n+1"}, {Page: "1"}}, Back: valid.Back}, "outside"},
+	}
+
+	for _, test := range tests {
+		err := validateImposition(test.template)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: expected error containing %q, got: %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestLoadImposition(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	config := `{
+  "printers": {},
+  "impositions": {
+    "6-up": {
+      "columns": 3, "rows": 2, "flip": "turn", "signature_sheets": 1,
+      "front": [{"page": "12*s+1"}, {"page": "12*s+3"}, {"page": "12*s+5"}, {"page": "12*s+7"}, {"page": "12*s+9"}, {"page": "12*s+11"}],
+      "back": [{"page": "12*s+6"}, {"page": "12*s+4"}, {"page": "12*s+2"}, {"page": "12*s+12"}, {"page": "12*s+10"}, {"page": "12*s+8"}]
+    }
+  }
+}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	template, err := loadImposition(configFile, "6-up")
+	if err != nil || template.Name != "6-up" || template.SheetPages() != 12 {
+		t.Errorf("Expected the 6-up template from the config file, got %+v (%v)", template, err)
+	}
+
+	template, err = loadImposition(configFile, "octavo")
+	if err != nil || template.SignatureSheets != 1 {
+		t.Errorf("Expected the built-in octavo, got %+v (%v)", template, err)
+	}
+
+	if _, err := loadImposition(configFile, "folio"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected error about an unknown template, got: %v", err)
+	}
+}
+
+func TestLoadImpositionYAML(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "6-up.yaml")
+	template := `name: 6-up
+columns: 3
+rows: 2
+flip: turn
+signature_sheets: 1
+front:
+  - page: 12*s+1
+  - page: 12*s+3
+  - page: 12*s+5
+  - page: 12*s+7
+  - page: 12*s+9
+  - page: 12*s+11
+back:
+  - page: 12*s+6
+  - page: 12*s+4
+  - page: 12*s+2
+  - page: 12*s+12
+  - page: 12*s+10
+  - page: 12*s+8
+`
+	if err := os.WriteFile(yamlFile, []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadImposition(filepath.Join(dir, "booklet-maker.json"), yamlFile)
+	if err != nil || loaded.Name != "6-up" || loaded.SignatureSheets != 1 || loaded.Back[3].Page != "12*s+12" {
+		t.Errorf("Expected the 6-up template from the YAML file, got %+v (%v)", loaded, err)
+	}
+
+	badFile := filepath.Join(dir, "bad.yml")
+	if err := os.WriteFile(badFile, []byte("columns: [3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadImposition(filepath.Join(dir, "booklet-maker.json"), badFile); err == nil || !strings.Contains(err.Error(), "invalid imposition template") {
+		t.Errorf("Expected error about the invalid YAML template, got: %v", err)
+	}
+}
@@ -6,6 +6,31 @@ import (
 	"testing"
 )
 
+// sourceDir is the package directory, for tests that read files of the repository
+var sourceDir string
+
+// TestMain runs the tests in a scratch directory, as booklet runs write their
+// page markers and print-ready folder next to the output
+func TestMain(m *testing.M) {
+	var err error
+	sourceDir, err = os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	dir, err := os.MkdirTemp("", "booklet-maker-test")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.Chdir(sourceDir)
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestMainFunction(t *testing.T) {
 	// Save original os.Args
 	origArgs := os.Args
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Page number styles, matching the /S styles of PDF page labels
+const (
+	NumberArabic     = "arabic" // 1, 2, 3
+	NumberRoman      = "roman"  // i, ii, iii
+	NumberRomanUpper = "Roman"  // I, II, III
+	NumberAlpha      = "alpha"  // a, b, c... aa, bb
+	NumberAlphaUpper = "Alpha"  // A, B, C... AA, BB
+	NumberNone       = "none"   // Counted but not numbered
+)
+
+var validNumberStyles = []string{NumberArabic, NumberRoman, NumberRomanUpper, NumberAlpha, NumberAlphaUpper, NumberNone}
+
+// Pages that keep their number but don't show it
+const (
+	SkipBlank   = "blank"   // Inserted blank pages
+	SkipChapter = "chapter" // Chapter-opening pages from the outline
+)
+
+var validNumberSkips = []string{SkipBlank, SkipChapter}
+
+// pageLabelStyles maps the number styles to the /S entry of a page label range
+var pageLabelStyles = map[string]string{
+	NumberArabic:     "D",
+	NumberRoman:      "r",
+	NumberRomanUpper: "R",
+	NumberAlpha:      "a",
+	NumberAlphaUpper: "A",
+}
+
+// NumberingRule numbers a range of source pages in one style
+type NumberingRule struct {
+	First  int // First source page of the range
+	Last   int // Last source page, 0 for the end of the book
+	Style  string
+	Start  int // Number of the first page in the range
+	Prefix string
+	Suffix string
+}
+
+// PageNumber is the number of one page of the numbered PDF
+type PageNumber struct {
+	Page    int // Page in the numbered PDF
+	Source  int // Source page, 0 for a blank
+	Number  int
+	Label   string // Prefix, formatted number and suffix
+	Printed bool   // False for unnumbered and skipped pages
+}
+
+// PageLabelRange is one entry of the /PageLabels number tree
+type PageLabelRange struct {
+	PageIndex int    // First page of the range, counting from 0
+	Style     string // /S entry, empty for labels without a number
+	Prefix    string
+	Start     int
+}
+
+// parseNumbering parses a numbering spec like "1-4=none;5-12=roman;13-=arabic,start=1,prefix=p. "
+//
+// A rule without a range applies from page 1 to the end.
+func parseNumbering(spec string) ([]NumberingRule, error) {
+	var rules []NumberingRule
+	for _, item := range strings.Split(spec, ";") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		fields := strings.Split(item, ",")
+		rule := NumberingRule{First: 1, Start: 1}
+
+		head := strings.TrimSpace(fields[0])
+		pageRange, style, hasRange := strings.Cut(head, "=")
+		if !hasRange {
+			style, pageRange = head, "1-"
+		}
+		rule.Style = strings.TrimSpace(style)
+		if !isValidOption(rule.Style, validNumberStyles) {
+			return nil, fmt.Errorf("numbering style must be one of %s, got %s", strings.Join(validNumberStyles, ", "), rule.Style)
+		}
+		first, last, err := parsePageRange(strings.TrimSpace(pageRange))
+		if err != nil {
+			return nil, err
+		}
+		rule.First, rule.Last = first, last
+
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			switch strings.TrimSpace(key) {
+			case "start":
+				rule.Start, err = strconv.Atoi(strings.TrimSpace(value))
+				if err != nil || rule.Start < 1 {
+					return nil, fmt.Errorf("numbering start must be a positive number, got %s", value)
+				}
+			case "prefix":
+				rule.Prefix = value
+			case "suffix":
+				rule.Suffix = value
+			default:
+				return nil, fmt.Errorf("unknown numbering option %q", field)
+			}
+		}
+
+		if len(rules) > 0 {
+			previous := rules[len(rules)-1]
+			if previous.Last == 0 || rule.First <= previous.Last {
+				return nil, fmt.Errorf("numbering range %s overlaps the previous range", pageRange)
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("numbering needs at least one rule")
+	}
+	return rules, nil
+}
+
+// parsePageRange parses "N", "N-M" or "N-" into first and last page, 0 for an open end
+func parsePageRange(pageRange string) (int, int, error) {
+	from, to, isRange := strings.Cut(pageRange, "-")
+	first, err := strconv.Atoi(from)
+	if err != nil || first < 1 {
+		return 0, 0, fmt.Errorf("invalid page range %q", pageRange)
+	}
+	if !isRange {
+		return first, first, nil
+	}
+	if to == "" {
+		return first, 0, nil
+	}
+	last, err := strconv.Atoi(to)
+	if err != nil || last < first {
+		return 0, 0, fmt.Errorf("invalid page range %q", pageRange)
+	}
+	return first, last, nil
+}
+
+// parseNumberSkip parses the comma separated list of pages that don't show their number
+func parseNumberSkip(spec string) (map[string]bool, error) {
+	skip := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !isValidOption(item, validNumberSkips) {
+			return nil, fmt.Errorf("number skip must be blank or chapter, got %s", item)
+		}
+		skip[item] = true
+	}
+	return skip, nil
+}
+
+// formatNumber writes a page number in the given style
+func formatNumber(n int, style string) string {
+	switch style {
+	case NumberRoman:
+		return strings.ToLower(romanNumeral(n))
+	case NumberRomanUpper:
+		return romanNumeral(n)
+	case NumberAlpha:
+		return strings.ToLower(alphaNumeral(n))
+	case NumberAlphaUpper:
+		return alphaNumeral(n)
+	case NumberNone:
+		return ""
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// romanNumeral writes n in uppercase roman numerals
+func romanNumeral(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	symbols := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var numeral strings.Builder
+	for i, value := range values {
+		for n >= value {
+			numeral.WriteString(symbols[i])
+			n -= value
+		}
+	}
+	return numeral.String()
+}
+
+// alphaNumeral writes n as A-Z, then AA-ZZ and so on, as PDF page labels do
+func alphaNumeral(n int) string {
+	if n < 1 {
+		return ""
+	}
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
+
+// ruleFor returns the index of the rule that numbers a source page, -1 for none
+func ruleFor(rules []NumberingRule, source int) int {
+	for i, rule := range rules {
+		if source >= rule.First && (rule.Last == 0 || source <= rule.Last) {
+			return i
+		}
+	}
+	return -1
+}
+
+// numberPages numbers every page of the document in the given page order
+//
+// Blanks continue the range of the page before them (front blanks take the
+// range of the first page) and use up a number like any other page. Skipped
+// pages keep their number without showing it. Arabic numbers are written in
+// the numeral system; the page labels keep the /S style viewers understand.
+func numberPages(order []int, rules []NumberingRule, skip map[string]bool, chapters []int, numerals string) ([]PageNumber, []PageLabelRange) {
+	chapterStart := make(map[int]bool, len(chapters))
+	for _, page := range chapters {
+		chapterStart[page] = true
+	}
+
+	current := -1
+	for _, source := range order {
+		if source > 0 {
+			current = ruleFor(rules, source)
+			break
+		}
+	}
+
+	var numbers []PageNumber
+	var labels []PageLabelRange
+	n, previous := 0, -2
+	for i, source := range order {
+		if source > 0 {
+			current = ruleFor(rules, source)
+		}
+		if current != previous {
+			label := PageLabelRange{PageIndex: i}
+			n = 1
+			if current >= 0 {
+				rule := rules[current]
+				label.Style, label.Prefix, label.Start = pageLabelStyles[rule.Style], rule.Prefix, rule.Start
+				n = rule.Start
+			}
+			labels = append(labels, label)
+			previous = current
+		}
+
+		number := PageNumber{Page: i + 1, Source: source, Number: n}
+		if current >= 0 && rules[current].Style != NumberNone {
+			rule := rules[current]
+			formatted := formatNumber(n, rule.Style)
+			if rule.Style == NumberArabic {
+				formatted = localizeDigits(formatted, numerals)
+			}
+			number.Label = rule.Prefix + formatted + rule.Suffix
+			number.Printed = !(skip[SkipBlank] && source == 0) && !(skip[SkipChapter] && chapterStart[source])
+		}
+		numbers = append(numbers, number)
+		n++
+	}
+	return numbers, labels
+}
+
+// stampPageNumbers stamps the printed page numbers at the bottom center of each page
+func stampPageNumbers(pdfFile string, numbers []PageNumber) error {
+	printed := 0
+	for _, number := range numbers {
+		if number.Printed {
+			printed++
+		}
+	}
+	fmt.Printf("Numbering pages of %s, %d of %d pages show a number\n", pdfFile, printed, len(numbers))
+	for _, number := range numbers {
+		if number.Printed {
+			fmt.Printf("  Page %d: %q\n", number.Page, number.Label)
+		}
+	}
+
+	// In a real implementation, this would stamp each label with the helper.sh "pos:bc, ma:1 10" description
+	return nil
+}
+
+// writePageLabels writes the /PageLabels number tree so viewers show the same numbers
+func writePageLabels(pdfFile string, labels []PageLabelRange) error {
+	fmt.Printf("Writing page labels to %s, %d ranges\n", pdfFile, len(labels))
+	for _, label := range labels {
+		fmt.Printf("  From page %d: /S %q /P %q /St %d\n", label.PageIndex+1, label.Style, label.Prefix, label.Start)
+	}
+
+	// In a real implementation, this would set the /PageLabels entry of the document catalog
+	return nil
+}
+
+// applyNumbering numbers the pages of a PDF laid out in the given order and writes its page labels
+func applyNumbering(pdfFile string, order []int, numbering, numberSkip string, chapters []int, numerals string) ([]PageNumber, error) {
+	rules, err := parseNumbering(numbering)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := parseNumberSkip(numberSkip)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers, labels := numberPages(order, rules, skip, chapters, numerals)
+	err = stampPageNumbers(pdfFile, numbers)
+	if err == nil {
+		err = writePageLabels(pdfFile, labels)
+	}
+	return numbers, err
+}
+
+// numberingChapters returns the chapter pages for -number-skip chapter, reading the outline if needed
+func numberingChapters(config *BookletConfig, outline []Bookmark) ([]int, error) {
+	if !strings.Contains(config.NumberSkip, SkipChapter) {
+		return nil, nil
+	}
+	if outline == nil {
+		var err error
+		outline, err = readOutline(config.InputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outline: %w", err)
+		}
+	}
+	return chapterStarts(outline), nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNumbering(t *testing.T) {
+	rules, err := parseNumbering("1=none; 2-5=roman ;6-=arabic,start=3,prefix=p. ,suffix= -")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []NumberingRule{
+		{First: 1, Last: 1, Style: NumberNone, Start: 1},
+		{First: 2, Last: 5, Style: NumberRoman, Start: 1},
+		{First: 6, Last: 0, Style: NumberArabic, Start: 3, Prefix: "p. ", Suffix: " -"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rules)
+	}
+
+	rules, _ = parseNumbering("arabic")
+	if len(rules) != 1 || rules[0].First != 1 || rules[0].Last != 0 {
+		t.Errorf("Expected a rule for the whole book, got %+v", rules)
+	}
+
+	for _, spec := range []string{"", "1-4=greek", "4-2=arabic", "1-=roman;5-=arabic", "1-4=roman;3-=arabic", "arabic,start=0", "arabic,color=red"} {
+		if _, err := parseNumbering(spec); err == nil {
+			t.Errorf("Expected error for %q, got nil", spec)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n        int
+		style    string
+		expected string
+	}{
+		{4, NumberArabic, "4"},
+		{4, NumberRoman, "iv"},
+		{1994, NumberRomanUpper, "MCMXCIV"},
+		{3, NumberAlpha, "c"},
+		{28, NumberAlphaUpper, "BB"},
+		{7, NumberNone, ""},
+	}
+
+	for _, test := range tests {
+		if got := formatNumber(test.n, test.style); got != test.expected {
+			t.Errorf("Expected %d in %s to be %q, got %q", test.n, test.style, test.expected, got)
+		}
+	}
+}
+
+func TestNumberPages(t *testing.T) {
+	rules, _ := parseNumbering("1-2=roman;3-=arabic,prefix=p")
+	skip, _ := parseNumberSkip("blank,chapter")
+	order := []int{0, 1, 2, 0, 3, 4}
+
+	numbers, labels := numberPages(order, rules, skip, []int{3}, NumeralsWestern)
+
+	expectedLabels := []string{"i", "ii", "iii", "iv", "p1", "p2"}
+	expectedPrinted := []bool{false, true, true, false, false, true}
+	for i, number := range numbers {
+		if number.Label != expectedLabels[i] || number.Printed != expectedPrinted[i] {
+			t.Errorf("Page %d: expected %q (printed=%v), got %q (printed=%v)",
+				i+1, expectedLabels[i], expectedPrinted[i], number.Label, number.Printed)
+		}
+	}
+
+	expected := []PageLabelRange{
+		{PageIndex: 0, Style: "r", Start: 1},
+		{PageIndex: 4, Style: "D", Prefix: "p", Start: 1},
+	}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Errorf("Expected page labels %+v, got %+v", expected, labels)
+	}
+}
+
+func TestNumberPagesUncovered(t *testing.T) {
+	rules, _ := parseNumbering("3-=arabic,start=5")
+	numbers, labels := numberPages([]int{1, 2, 3, 4}, rules, nil, nil, NumeralsWestern)
+
+	if numbers[0].Printed || numbers[1].Label != "" || numbers[2].Label != "5" || numbers[3].Label != "6" {
+		t.Errorf("Unexpected numbers %+v", numbers)
+	}
+	if len(labels) != 2 || labels[0].Style != "" || labels[1].PageIndex != 2 || labels[1].Start != 5 {
+		t.Errorf("Unexpected page labels %+v", labels)
+	}
+}
+
+func TestParseNumberSkip(t *testing.T) {
+	skip, err := parseNumberSkip("blank, chapter")
+	if err != nil || !skip[SkipBlank] || !skip[SkipChapter] {
+		t.Errorf("Expected blank and chapter skips, got %v (%v)", skip, err)
+	}
+	if _, err := parseNumberSkip("cover"); err == nil {
+		t.Error("Expected error for an unknown skip, got nil")
+	}
+}
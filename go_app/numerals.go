@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Numeral systems for every number stamped on the pages
+const (
+	NumeralsWestern     = "western"      // 0123456789
+	NumeralsArabicIndic = "arabic-indic" // ٠١٢٣٤٥٦٧٨٩
+	NumeralsPersian     = "persian"      // ۰۱۲۳۴۵۶۷۸۹, Eastern Arabic-Indic
+	NumeralsDevanagari  = "devanagari"   // ०१२३४५६७८९
+	NumeralsRoman       = "roman"        // I, II, III
+)
+
+var validNumeralSystems = []string{NumeralsWestern, NumeralsArabicIndic, NumeralsPersian, NumeralsDevanagari, NumeralsRoman}
+
+// defaultFontFile is the font the marks are stamped with, as installed by the shell scripts
+const defaultFontFile = "fonts/BigBlueTermPlusNerdFontMono-Regular.ttf"
+
+// numeralZero is the digit zero of each positional numeral system; the other digits follow it
+var numeralZero = map[string]rune{
+	NumeralsWestern:     '0',
+	NumeralsArabicIndic: '٠',
+	NumeralsPersian:     '۰',
+	NumeralsDevanagari:  '०',
+}
+
+// localizeDigits rewrites every run of Western digits in text in the numeral system
+//
+// Roman numerals replace the value of the run, so a zero-padded "07" becomes "VII".
+func localizeDigits(text, system string) string {
+	if system == "" || system == NumeralsWestern {
+		return text
+	}
+
+	var localized strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if runes[i] < '0' || runes[i] > '9' {
+			localized.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+			j++
+		}
+		digits := string(runes[i:j])
+		if system == NumeralsRoman {
+			n, _ := strconv.Atoi(digits)
+			localized.WriteString(romanNumeral(n))
+		} else {
+			for _, digit := range digits {
+				localized.WriteRune(numeralZero[system] + digit - '0')
+			}
+		}
+		i = j
+	}
+	return localized.String()
+}
+
+// numeralGlyphs returns the characters the font needs for a numeral system
+func numeralGlyphs(system string) []rune {
+	if system == NumeralsRoman {
+		return []rune("IVXLCDM")
+	}
+	zero, ok := numeralZero[system]
+	if !ok {
+		zero = '0'
+	}
+	glyphs := make([]rune, 10)
+	for i := range glyphs {
+		glyphs[i] = zero + rune(i)
+	}
+	return glyphs
+}
+
+// checkNumeralGlyphs makes sure the stamping font can draw the numerals
+func checkNumeralGlyphs(fontFile, system string) error {
+	if system == "" || system == NumeralsWestern {
+		return nil
+	}
+
+	data, err := os.ReadFile(fontFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("font file %s not found, pass -font with a font that has %s numerals", fontFile, system)
+	}
+	if err != nil {
+		return err
+	}
+
+	missing, err := missingGlyphs(data, numeralGlyphs(system))
+	if err != nil {
+		return fmt.Errorf("failed to read font %s: %w", fontFile, err)
+	}
+	if len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, r := range missing {
+			names[i] = fmt.Sprintf("%c (U+%04X)", r, r)
+		}
+		return fmt.Errorf("font %s has no glyphs for %s numerals: missing %s", fontFile, system, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// missingGlyphs returns the runes a TrueType or OpenType font has no glyph for
+//
+// Only the Unicode character map is read: a format 12 subtable if there is
+// one, otherwise a format 4 subtable.
+func missingGlyphs(font []byte, runes []rune) ([]rune, error) {
+	cmap, err := fontTable(font, "cmap")
+	if err != nil {
+		return nil, err
+	}
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("cmap table is too short")
+	}
+
+	var format4, format12 []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count; i++ {
+		record := 4 + 8*i
+		if record+8 > len(cmap) {
+			return nil, fmt.Errorf("cmap table is truncated")
+		}
+		platform := binary.BigEndian.Uint16(cmap[record:])
+		encoding := binary.BigEndian.Uint16(cmap[record+2:])
+		offset := int(binary.BigEndian.Uint32(cmap[record+4:]))
+		if offset+2 > len(cmap) || !(platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	var covered func(rune) bool
+	switch {
+	case format12 != nil:
+		covered = func(r rune) bool { return format12Glyph(format12, r) != 0 }
+	case format4 != nil:
+		covered = func(r rune) bool { return format4Glyph(format4, r) != 0 }
+	default:
+		return nil, fmt.Errorf("no Unicode character map")
+	}
+
+	var missing []rune
+	for _, r := range runes {
+		if !unicode.IsSpace(r) && !covered(r) {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
+
+// fontTable returns the named table of a font file
+func fontTable(font []byte, tag string) ([]byte, error) {
+	if len(font) < 12 {
+		return nil, fmt.Errorf("not a TrueType or OpenType font")
+	}
+	count := int(binary.BigEndian.Uint16(font[4:]))
+	for i := 0; i < count; i++ {
+		record := 12 + 16*i
+		if record+16 > len(font) {
+			break
+		}
+		if string(font[record:record+4]) != tag {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(font[record+8:]))
+		length := int(binary.BigEndian.Uint32(font[record+12:]))
+		if offset+length > len(font) {
+			return nil, fmt.Errorf("%s table is truncated", tag)
+		}
+		return font[offset : offset+length], nil
+	}
+	return nil, fmt.Errorf("no %s table", tag)
+}
+
+// format4Glyph looks up a BMP character in a segment mapping subtable
+func format4Glyph(table []byte, r rune) uint16 {
+	if r > 0xFFFF || len(table) < 14 {
+		return 0
+	}
+	segments := int(binary.BigEndian.Uint16(table[6:])) / 2
+	ends := 14
+	starts := ends + 2*segments + 2
+	deltas := starts + 2*segments
+	rangeOffsets := deltas + 2*segments
+	if rangeOffsets+2*segments > len(table) {
+		return 0
+	}
+
+	c := uint16(r)
+	for i := 0; i < segments; i++ {
+		end := binary.BigEndian.Uint16(table[ends+2*i:])
+		if c > end {
+			continue
+		}
+		start := binary.BigEndian.Uint16(table[starts+2*i:])
+		if c < start {
+			return 0
+		}
+		delta := binary.BigEndian.Uint16(table[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(table[rangeOffsets+2*i:]))
+		if rangeOffset == 0 {
+			return c + delta
+		}
+		// The offset is relative to its own position in the idRangeOffset array
+		index := rangeOffsets + 2*i + rangeOffset + 2*int(c-start)
+		if index+2 > len(table) {
+			return 0
+		}
+		glyph := binary.BigEndian.Uint16(table[index:])
+		if glyph == 0 {
+			return 0
+		}
+		return glyph + delta
+	}
+	return 0
+}
+
+// format12Glyph looks up a character in a segmented coverage subtable
+func format12Glyph(table []byte, r rune) uint32 {
+	if len(table) < 16 {
+		return 0
+	}
+	groups := int(binary.BigEndian.Uint32(table[12:]))
+	for i := 0; i < groups; i++ {
+		group := 16 + 12*i
+		if group+12 > len(table) {
+			return 0
+		}
+		start := binary.BigEndian.Uint32(table[group:])
+		end := binary.BigEndian.Uint32(table[group+4:])
+		if uint32(r) >= start && uint32(r) <= end {
+			return binary.BigEndian.Uint32(table[group+8:]) + uint32(r) - start
+		}
+	}
+	return 0
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testFont builds a minimal font whose character map covers the Western
+// digits and Arabic-Indic zero, but not Arabic-Indic one
+func testFont() []byte {
+	u16 := func(values ...uint16) []byte {
+		data := make([]byte, 2*len(values))
+		for i, v := range values {
+			binary.BigEndian.PutUint16(data[2*i:], v)
+		}
+		return data
+	}
+
+	var subtable []byte
+	subtable = append(subtable, u16(4, 0, 0, 6, 0, 0, 0)...)
+	subtable = append(subtable, u16(0x39, 0x661, 0xFFFF)...) // End codes
+	subtable = append(subtable, u16(0)...)
+	subtable = append(subtable, u16(0x30, 0x660, 0xFFFF)...) // Start codes
+	subtable = append(subtable, u16(uint16(1-0x30+0x10000), 0, 1)...)
+	subtable = append(subtable, u16(0, 4, 0)...) // The second segment points at the glyph array
+	subtable = append(subtable, u16(7, 0)...)
+	binary.BigEndian.PutUint16(subtable[2:], uint16(len(subtable)))
+
+	cmap := append(u16(0, 1, 3, 1), 0, 0, 0, 12)
+	cmap = append(cmap, subtable...)
+
+	font := append([]byte{0, 1, 0, 0}, u16(1, 0, 0, 0)...)
+	font = append(font, []byte("cmap")...)
+	font = append(font, 0, 0, 0, 0, 0, 0, 0, 28)
+	font = append(font, byte(len(cmap)>>24), byte(len(cmap)>>16), byte(len(cmap)>>8), byte(len(cmap)))
+	return append(font, cmap...)
+}
+
+func TestLocalizeDigits(t *testing.T) {
+	tests := []struct {
+		text     string
+		system   string
+		expected string
+	}{
+		{"07", NumeralsWestern, "07"},
+		{"07", NumeralsArabicIndic, "٠٧"},
+		{"p. 129", NumeralsPersian, "p. ۱۲۹"},
+		{"12*", NumeralsDevanagari, "१२*"},
+		{"07", NumeralsRoman, "VII"},
+		{"A2", NumeralsRoman, "AII"},
+		{"VOL 03", NumeralsArabicIndic, "VOL ٠٣"},
+	}
+
+	for _, test := range tests {
+		if got := localizeDigits(test.text, test.system); got != test.expected {
+			t.Errorf("Expected %q in %s to be %q, got %q", test.text, test.system, test.expected, got)
+		}
+	}
+}
+
+func TestMissingGlyphs(t *testing.T) {
+	font := testFont()
+
+	missing, err := missingGlyphs(font, numeralGlyphs(NumeralsWestern))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("Expected all Western digits covered, got %q (%v)", string(missing), err)
+	}
+
+	missing, _ = missingGlyphs(font, []rune("٠١٢"))
+	if !reflect.DeepEqual(missing, []rune("١٢")) {
+		t.Errorf("Expected ١ and ٢ missing, got %q", string(missing))
+	}
+
+	if _, err := missingGlyphs([]byte("not a font"), []rune("1")); err == nil {
+		t.Error("Expected error for a file that is not a font, got nil")
+	}
+}
+
+func TestCheckNumeralGlyphs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "digits.ttf")
+	if err := os.WriteFile(path, testFont(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkNumeralGlyphs(path, NumeralsWestern); err != nil {
+		t.Errorf("Expected Western digits to pass, got: %v", err)
+	}
+
+	err := checkNumeralGlyphs(path, NumeralsArabicIndic)
+	if err == nil || !strings.Contains(err.Error(), "no glyphs for arabic-indic numerals") || !strings.Contains(err.Error(), "U+0661") {
+		t.Errorf("Expected error naming the missing glyphs, got: %v", err)
+	}
+
+	err = checkNumeralGlyphs(filepath.Join(t.TempDir(), "missing.ttf"), NumeralsPersian)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected error about the missing font file, got: %v", err)
+	}
+}
+
+func TestNumberPagesNumerals(t *testing.T) {
+	rules, _ := parseNumbering("1-2=roman;3-=arabic,start=9")
+	numbers, _ := numberPages([]int{1, 2, 3, 4}, rules, nil, nil, NumeralsArabicIndic)
+
+	expected := []string{"i", "ii", "٩", "١٠"}
+	for i, number := range numbers {
+		if number.Label != expected[i] {
+			t.Errorf("Page %d: expected %q, got %q", i+1, expected[i], number.Label)
+		}
+	}
+}
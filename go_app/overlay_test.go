@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSheetSlots(t *testing.T) {
+	tests := []struct {
+		pagesPerSheet int
+		columns       int
+		rows          int
+	}{
+		{1, 1, 1},
+		{2, 1, 2},
+		{4, 2, 2},
+		{8, 2, 4},
+	}
+
+	for _, test := range tests {
+		cells := sheetSlots(a4Width, a4Height, test.pagesPerSheet, BindingLeft)
+		if len(cells) != test.pagesPerSheet {
+			t.Fatalf("%d-up: expected %d booklet pages, got %d", test.pagesPerSheet, test.pagesPerSheet, len(cells))
+		}
+
+		width, height := orientSheet(a4Width, a4Height, isLandscapeSheet(test.pagesPerSheet))
+		last := cells[len(cells)-1][1]
+		if math.Abs(last.X+last.Width-width) > 1 {
+			t.Errorf("%d-up: expected the last slot at the right edge, got %+v", test.pagesPerSheet, last)
+		}
+		if math.Abs(cells[0][0].Y+cells[0][0].Height-height) > 1 || math.Abs(last.Y) > 1 {
+			t.Errorf("%d-up: expected slots from the top to the bottom edge, got %+v and %+v", test.pagesPerSheet, cells[0][0], last)
+		}
+		columns := 0
+		for _, cell := range cells {
+			if cell[0].Y == cells[0][0].Y {
+				columns++
+			}
+		}
+		if columns != test.columns || len(cells)/columns != test.rows {
+			t.Errorf("%d-up: expected %dx%d booklet pages, got %d columns", test.pagesPerSheet, test.columns, test.rows, columns)
+		}
+		if cells[0][1].X != cells[0][0].X+cells[0][0].Width {
+			t.Errorf("%d-up: expected the two slots of a booklet page side by side", test.pagesPerSheet)
+		}
+	}
+}
+
+func TestOverlaySlots(t *testing.T) {
+	order := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	sides := imposeBookletSides(order, 4, "LTR")
+	files := planPrintFiles("booklet.pdf", len(sides), 4, 2, PrintOptions{Mode: DuplexManual, Face: FaceUp})
+	pages := []PageInfo{{Number: 7, Rotate: 90}}
+
+	slots := overlaySlots(files, sides, 4, a4Width, a4Height, 2, BindingLeft, pages)
+	if len(slots) != 8 {
+		t.Fatalf("Expected 8 slots, got %d", len(slots))
+	}
+
+	// The front of the only sheet holds booklet pages 1 and 3
+	if slots[0].Marker != 8 || slots[1].Marker != 1 || slots[2].Marker != 6 {
+		t.Errorf("Expected pages 8, 1, 6 on the front, got %d, %d, %d", slots[0].Marker, slots[1].Marker, slots[2].Marker)
+	}
+	back := slots[5]
+	if !back.Back || back.Marker != 7 || back.Rotation != 270 {
+		t.Errorf("Expected page 7 on the back rotated 180 plus 90, got %+v", back)
+	}
+	if back.Label != "p.7 sig 1 sheet 1 back rot 270" {
+		t.Errorf("Unexpected label %q", back.Label)
+	}
+}
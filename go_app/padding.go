@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PaddingPolicy decides how many blanks are added and where the end blanks go
+//
+// It is parsed from a comma separated -padding spec, e.g. "front=0,aligned,before-last".
+type PaddingPolicy struct {
+	Front      int  // Blanks before the first page, -1 for the -blank default
+	Back       int  // Blanks after the last page before filling the signature, -1 for the bookit.sh rule
+	Aligned    bool // No back blanks if the count is already a multiple of 4
+	BeforeLast bool // Insert the end blanks before the last page
+	BeforePage int  // Insert the end blanks before this input page, 0 for the end
+}
+
+// PaddingPlan is the number and position of the blanks for one book
+type PaddingPlan struct {
+	Front    int
+	Chapters int // Blanks that move chapters to a recto
+	Back     int // Blanks that close the last sheet
+	Fill     int // Blanks that fill the last signature
+	Before   int // Input page the end blanks are inserted before, 0 for the end
+}
+
+// defaultPadding is the bookit.sh rule
+var defaultPadding = PaddingPolicy{Front: -1, Back: -1}
+
+// Blanks returns the total number of blank pages in the plan
+func (plan PaddingPlan) Blanks() int {
+	return plan.Front + plan.Chapters + plan.Back + plan.Fill
+}
+
+// parsePadding parses a -padding spec, an empty spec is the bookit.sh rule
+func parsePadding(spec string) (PaddingPolicy, error) {
+	policy := defaultPadding
+	if spec == "" || spec == "default" {
+		return policy, nil
+	}
+
+	for _, token := range strings.Split(spec, ",") {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(token), "=")
+		switch {
+		case key == "aligned" && !hasValue:
+			policy.Aligned = true
+		case key == "before-last" && !hasValue:
+			policy.BeforeLast = true
+		case (key == "front" || key == "back" || key == "before") && hasValue:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || (key == "before" && n == 0) {
+				return PaddingPolicy{}, fmt.Errorf("invalid padding value %q", token)
+			}
+			switch key {
+			case "front":
+				policy.Front = n
+			case "back":
+				policy.Back = n
+			default:
+				policy.BeforePage = n
+			}
+		default:
+			return PaddingPolicy{}, fmt.Errorf("unknown padding option %q", token)
+		}
+	}
+
+	if policy.BeforeLast && policy.BeforePage > 0 {
+		return PaddingPolicy{}, fmt.Errorf("padding can't use both before-last and before=%d", policy.BeforePage)
+	}
+	return policy, nil
+}
+
+// planPadding computes the blanks for input pages first..last
+//
+// Chapters are the input pages that must start on a recto. Without a policy
+// or chapters, -blank 0 adds no blanks at all, as in bookit.sh.
+func planPadding(first, last, addBlank, pagesPerSignature int, policy PaddingPolicy, chapters []int) (PaddingPlan, error) {
+	if addBlank == 0 && policy == defaultPadding && len(chapters) == 0 {
+		return PaddingPlan{}, nil
+	}
+
+	plan := PaddingPlan{Front: policy.Front}
+	if plan.Front < 0 {
+		plan.Front = frontBlankCount(addBlank)
+	}
+	switch {
+	case policy.BeforeLast:
+		plan.Before = last
+	case policy.BeforePage > 0:
+		if policy.BeforePage < first || policy.BeforePage > last {
+			return PaddingPlan{}, fmt.Errorf("padding page %d is outside pages %d-%d", policy.BeforePage, first, last)
+		}
+		plan.Before = policy.BeforePage
+	}
+	plan.Chapters = len(chapterBreaks(chapters, first, last, plan.Front, plan.Before))
+	total := last - first + 1 + plan.Front + plan.Chapters
+
+	switch {
+	case policy.Back >= 0:
+		plan.Back = policy.Back
+	case policy.Aligned:
+		plan.Back = (4 - total%4) % 4
+	case total%4 == 1 || total%4 == 3:
+		// bookit.sh adds 3 for odd counts and 2 otherwise, even when already aligned
+		plan.Back = 3
+	default:
+		plan.Back = 2
+	}
+	total += plan.Back
+
+	// Fill the last signature
+	if pagesPerSignature > 0 && total%pagesPerSignature != 0 {
+		plan.Fill = pagesPerSignature - total%pagesPerSignature
+	}
+
+	return plan, nil
+}
+
+// reportPadding prints where the blanks of the plan go
+func reportPadding(plan PaddingPlan, preparedPages int) {
+	if plan.Blanks() == 0 {
+		fmt.Printf("Padding: no blank pages, %d pages\n", preparedPages)
+		return
+	}
+
+	where := "at the end"
+	if plan.Before > 0 {
+		where = fmt.Sprintf("before page %d", plan.Before)
+	}
+	fmt.Printf("Padding: %d blank pages, %d pages\n", plan.Blanks(), preparedPages)
+	fmt.Printf("  %d at the front, %d to close the last sheet and %d to fill the last signature %s\n",
+		plan.Front, plan.Back, plan.Fill, where)
+	if plan.Chapters > 0 {
+		fmt.Printf("  %d before chapters so they start on a recto\n", plan.Chapters)
+	}
+}
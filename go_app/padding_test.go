@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePadding(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected PaddingPolicy
+	}{
+		{"", defaultPadding},
+		{"default", defaultPadding},
+		{"aligned", PaddingPolicy{Front: -1, Back: -1, Aligned: true}},
+		{"front=0,back=1", PaddingPolicy{Front: 0, Back: 1}},
+		{"front=2, before-last", PaddingPolicy{Front: 2, Back: -1, BeforeLast: true}},
+		{"before=10", PaddingPolicy{Front: -1, Back: -1, BeforePage: 10}},
+	}
+
+	for _, test := range tests {
+		policy, err := parsePadding(test.spec)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", test.spec, err)
+			continue
+		}
+		if policy != test.expected {
+			t.Errorf("For %q expected %+v, got %+v", test.spec, test.expected, policy)
+		}
+	}
+
+	for _, spec := range []string{"front=-1", "back=x", "before=0", "middle", "aligned=1", "before-last,before=3"} {
+		if _, err := parsePadding(spec); err == nil {
+			t.Errorf("Expected error for %q, got nil", spec)
+		}
+	}
+}
+
+func TestPlanPadding(t *testing.T) {
+	tests := []struct {
+		name     string
+		pages    int
+		addBlank int
+		spec     string
+		expected PaddingPlan
+	}{
+		{"bookit.sh rule", 10, 1, "", PaddingPlan{Front: 2, Back: 2, Fill: 2}},
+		{"no blanks", 10, 0, "", PaddingPlan{}},
+		{"aligned adds nothing", 10, 1, "aligned", PaddingPlan{Front: 2}},
+		{"aligned closes the sheet", 11, 1, "aligned", PaddingPlan{Front: 2, Back: 3}},
+		{"fixed counts", 10, 1, "front=0,back=1", PaddingPlan{Back: 1, Fill: 1}},
+		{"before last", 11, 1, "aligned,before-last", PaddingPlan{Front: 2, Back: 3, Before: 11}},
+		{"before page", 11, 0, "aligned,before=5", PaddingPlan{Back: 1, Before: 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, _ := parsePadding(test.spec)
+			plan, err := planPadding(1, test.pages, test.addBlank, 4, policy, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if plan != test.expected {
+				t.Errorf("Expected %+v, got %+v", test.expected, plan)
+			}
+		})
+	}
+
+	policy, _ := parsePadding("before=20")
+	if _, err := planPadding(1, 10, 1, 4, policy, nil); err == nil {
+		t.Error("Expected error for a page outside the book, got nil")
+	}
+}
+
+func TestPaddedOrderKeepsBackCoverLast(t *testing.T) {
+	policy, _ := parsePadding("front=0,aligned,before-last")
+	order, plan, err := coverPageOrder(5, 1, 8, CoverNone, policy, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []int{1, 2, 3, 4, 0, 0, 0, 5}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected %v, got %v", expected, order)
+	}
+	if plan.Blanks() != 3 {
+		t.Errorf("Expected 3 blanks, got %d", plan.Blanks())
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Perfect binding allowances in mm
+const (
+	defaultGutter = 3.0 // Extra inner margin, glued pages don't open flat
+	defaultGrind  = 3.0 // Spine milled off and roughened before gluing
+)
+
+// defaultGSM is the paper weight the stack height is estimated with when neither caliper nor GSM is given
+const defaultGSM = 80.0
+
+// validatePerfectBinding checks the layout glues single leaves on a side edge
+func validatePerfectBinding(pagesPerSheet int, binding, imposition string, cutStack, repeat bool) error {
+	if pagesPerSheet != 1 && pagesPerSheet != 2 {
+		return fmt.Errorf("perfect binding needs 1 or 2 pages per sheet, got %d", pagesPerSheet)
+	}
+	if binding == BindingTop {
+		return fmt.Errorf("perfect binding glues the left or right edge, not the top")
+	}
+	if imposition != "" || cutStack || repeat {
+		return fmt.Errorf("perfect binding can't be combined with imposition templates, cut-and-stack or repeat")
+	}
+	return nil
+}
+
+// printLayout returns the n-up the sheet orientation follows
+//
+// A perfect-bound cell holds half a spread, which turns the sheet like
+// doubling the n-up does.
+func printLayout(pagesPerSheet int, opts PrintOptions) int {
+	if opts.Perfect {
+		return 2 * pagesPerSheet
+	}
+	return pagesPerSheet
+}
+
+// perfectBackRotation returns the back rotation for perfect-bound sheets
+//
+// The leaves turn about the glued edge, which runs like a side fold through
+// the sheet of twice the pages. A hand-fed stack of single leaves is turned
+// over its long edge, as a reader turns them.
+func perfectBackRotation(mode string, pagesPerSheet int, binding string) int {
+	if mode != DuplexLong && mode != DuplexShort {
+		mode = DuplexLong
+	}
+	return backRotation(mode, 2*pagesPerSheet, binding)
+}
+
+// perfectSlotSize returns the portrait slot of one page when the sheet carries pagesPerSheet pages
+func perfectSlotSize(sheetWidth, sheetHeight float64, pagesPerSheet int) (float64, float64) {
+	w, h := sheetWidth, sheetHeight
+	for slots := pagesPerSheet; slots > 1; slots /= 2 {
+		if w > h {
+			w /= 2
+		} else {
+			h /= 2
+		}
+	}
+	return math.Min(w, h), math.Max(w, h)
+}
+
+// gutterShift returns the horizontal shift in points that moves a page away from the glued edge
+//
+// LTR rectos are glued on the left and move right, versos move left; RTL
+// books mirror both.
+func gutterShift(position int, direction string, gutter, grind float64) float64 {
+	shift := (gutter + grind) * mmToPoints
+	if isRecto(position) != (direction == "RTL") {
+		return shift
+	}
+	return -shift
+}
+
+// shiftGutters moves every placed page away from the glued edge, marking the pages pushed out of their slot
+func shiftGutters(placements []Placement, order []int, direction string, gutter, grind, slotWidth float64) []Placement {
+	positions := make(map[int]int, len(order))
+	for i, page := range order {
+		if page > 0 {
+			positions[page] = i + 1
+		}
+	}
+
+	shifted := make([]Placement, len(placements))
+	for i, placement := range placements {
+		if position, ok := positions[placement.Page]; ok {
+			placement.OffsetX += gutterShift(position, direction, gutter, grind)
+			if placement.OffsetX < 0 || placement.OffsetX+placement.Width > slotWidth {
+				placement.Cropped = true
+			}
+		}
+		shifted[i] = placement
+	}
+	return shifted
+}
+
+// stackHeight estimates the thickness of the glued book block in mm, the width of the cover spine
+//
+// It also returns the caliper per leaf, estimated from the paper weight
+// when no caliper is given.
+func stackHeight(pageCount int, caliper, gsm float64) (float64, float64, bool) {
+	if caliper <= 0 && gsm <= 0 {
+		gsm = defaultGSM
+	}
+	caliper, estimated := paperCaliper(caliper, gsm)
+	return spineWidth(pageCount, caliper), caliper, estimated
+}
+
+// writePerfectBound lays the prepared pages out in reading order, one or two pages per sheet side
+func writePerfectBound(inputFile, outputFile string, pagesPerSheet int) error {
+	fmt.Printf("Creating perfect-bound layout: %s -> %s, pagesPerSheet: %d\n", inputFile, outputFile, pagesPerSheet)
+	if pagesPerSheet == 2 {
+		fmt.Println("  pdfcpu nup -- \"or:rd, g:off, ma:0\" 2")
+	}
+	// In a real implementation, this would copy the pages in order, two up for cut sheets
+	return nil
+}
+
+// reportStackHeight prints the estimated spine width and the cover command that uses it
+func reportStackHeight(pageCount int, caliper, gsm float64) float64 {
+	height, leafCaliper, estimated := stackHeight(pageCount, caliper, gsm)
+	fmt.Printf("Stack height: %.2f mm for %d pages, %d leaves of %.3f mm", height, pageCount, (pageCount+1)/2, leafCaliper)
+	if estimated {
+		fmt.Print(" (estimated from the paper weight)")
+	}
+	fmt.Println()
+	fmt.Printf("  Spine: booklet-maker cover -page-count %d -caliper %.3f\n", pageCount, leafCaliper)
+	return height
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValidatePerfectBinding(t *testing.T) {
+	tests := []struct {
+		pagesPerSheet int
+		binding       string
+		imposition    string
+		cutStack      bool
+		valid         bool
+	}{
+		{1, "", "", false, true},
+		{2, BindingRight, "", false, true},
+		{4, "", "", false, false},
+		{1, BindingTop, "", false, false},
+		{2, "", "quarto", false, false},
+		{2, "", "", true, false},
+	}
+
+	for _, test := range tests {
+		err := validatePerfectBinding(test.pagesPerSheet, test.binding, test.imposition, test.cutStack, false)
+		if (err == nil) != test.valid {
+			t.Errorf("Expected valid=%v for %+v, got %v", test.valid, test, err)
+		}
+	}
+}
+
+func TestPerfectSlotSize(t *testing.T) {
+	width, height := perfectSlotSize(a4Width, a4Height, 1)
+	if width != a4Width || height != a4Height {
+		t.Errorf("Expected a full A4 slot for 1-up, got %.2fx%.2f", width, height)
+	}
+	width, height = perfectSlotSize(a4Height, a4Width, 2)
+	if math.Abs(width-a4Height/2) > 0.01 || height != a4Width {
+		t.Errorf("Expected an A5 slot for 2-up, got %.2fx%.2f", width, height)
+	}
+}
+
+func TestGutterShift(t *testing.T) {
+	shift := 6 * mmToPoints
+	tests := []struct {
+		position  int
+		direction string
+		expected  float64
+	}{
+		{1, "LTR", shift},
+		{2, "LTR", -shift},
+		{1, "RTL", -shift},
+		{2, "RTL", shift},
+	}
+
+	for _, test := range tests {
+		if got := gutterShift(test.position, test.direction, 3, 3); math.Abs(got-test.expected) > 0.001 {
+			t.Errorf("Expected %.2f for page %d %s, got %.2f", test.expected, test.position, test.direction, got)
+		}
+	}
+}
+
+func TestShiftGutters(t *testing.T) {
+	placements := []Placement{
+		{Page: 1, OffsetX: 5, Width: 400},
+		{Page: 2, OffsetX: 10, Width: 410},
+		{Page: 3, OffsetX: 0, Width: 420},
+	}
+	// A front blank makes input page 1 a verso
+	shifted := shiftGutters(placements, []int{0, 1, 2, 3}, "LTR", 2, 1, 420)
+
+	shift := 3 * mmToPoints
+	if math.Abs(shifted[0].OffsetX-(5-shift)) > 0.001 || !shifted[0].Cropped {
+		t.Errorf("Expected the verso moved left past the slot edge, got %+v", shifted[0])
+	}
+	if math.Abs(shifted[1].OffsetX-(10+shift)) > 0.001 || !shifted[1].Cropped {
+		t.Errorf("Expected the recto moved right past the slot edge, got %+v", shifted[1])
+	}
+	if placements[0].OffsetX != 5 {
+		t.Errorf("Expected the placements to be left unchanged, got %+v", placements[0])
+	}
+}
+
+func TestStackHeight(t *testing.T) {
+	tests := []struct {
+		pages        int
+		caliper, gsm float64
+		expected     float64
+		estimated    bool
+	}{
+		{200, 0.1, 0, 10, false},
+		{201, 0.1, 0, 10.1, false},
+		{200, 0, 100, 12.5, true},
+		{200, 0, 0, 10, true}, // 80 gsm
+	}
+
+	for _, test := range tests {
+		height, _, estimated := stackHeight(test.pages, test.caliper, test.gsm)
+		if math.Abs(height-test.expected) > 0.001 || estimated != test.estimated {
+			t.Errorf("Expected %.2f mm (estimated=%v) for %+v, got %.2f (%v)", test.expected, test.estimated, test, height, estimated)
+		}
+	}
+}
+
+func TestPerfectPrintFiles(t *testing.T) {
+	opts := PrintOptions{Mode: DuplexManual, Face: FaceDown, Perfect: true}
+	files := planPrintFiles("book.pdf", 6, 0, 1, opts)
+	if len(files) != 2 || !reflect.DeepEqual(files[0].Sides[1].Pages, []int{3}) || !reflect.DeepEqual(files[1].Sides[1].Pages, []int{4}) {
+		t.Errorf("Expected sequential 1-up leaves, got %+v", files)
+	}
+	if files[1].Sides[0].Rotation != 0 {
+		t.Errorf("Expected hand-fed portrait 1-up backs not to turn, got %d", files[1].Sides[0].Rotation)
+	}
+	if files = planPrintFiles("book.pdf", 8, 0, 2, opts); files[1].Sides[0].Rotation != 180 {
+		t.Errorf("Expected hand-fed landscape 2-up backs to turn, got %d", files[1].Sides[0].Rotation)
+	}
+
+	// Two portrait pages make a landscape sheet
+	opts = PrintOptions{Mode: DuplexLong, Scope: DuplexPerSignature, Perfect: true, CutStack: true}
+	files = planPrintFiles("book.pdf", 8, 0, 2, opts)
+	if !reflect.DeepEqual(files[0].Sides[0].Pages, []int{1, 5}) {
+		t.Errorf("Expected pages 1 and 5 on the first front, got %v", files[0].Sides[0].Pages)
+	}
+	if files[0].Sides[1].Rotation != 180 {
+		t.Errorf("Expected landscape 2-up backs flipped on the long edge to turn, got %d", files[0].Sides[1].Rotation)
+	}
+	if files = planPrintFiles("book.pdf", 8, 0, 1, opts); files[0].Sides[1].Rotation != 0 {
+		t.Errorf("Expected portrait 1-up backs flipped on the long edge not to turn, got %d", files[0].Sides[1].Rotation)
+	}
+}
+
+func TestPerfectBindingPadding(t *testing.T) {
+	config := &BookletConfig{InputFile: "test.pdf", PagesPerSheet: 1, Sections: 8, AddBlank: 0, Padding: "back=0", PerfectBinding: true}
+	order, _, _, err := bookletPageOrder(config, 101)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(order) != 102 {
+		t.Errorf("Expected 101 pages padded to whole leaves, got %d", len(order))
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import "fmt"
+
+// Spread is a two-page opening of the folded book as the reader sees it
+//
+// A nil side is the inside of the cover, next to the first and last page.
+type Spread struct {
+	Number int
+	Left   *ReadPage
+	Right  *ReadPage
+}
+
+// previewSpreads pairs the folded reading order into openings
+//
+// The first page is a recto on its own; after that every verso faces the
+// next recto. Versos are on the left in LTR books and on the right in RTL books.
+func previewSpreads(read []ReadPage, direction string) []Spread {
+	var spreads []Spread
+	add := func(verso, recto *ReadPage) {
+		spread := Spread{Number: len(spreads) + 1, Left: verso, Right: recto}
+		if direction == "RTL" {
+			spread.Left, spread.Right = recto, verso
+		}
+		spreads = append(spreads, spread)
+	}
+
+	if len(read) == 0 {
+		return nil
+	}
+	add(nil, &read[0])
+	for i := 1; i < len(read); i += 2 {
+		if i+1 < len(read) {
+			add(&read[i], &read[i+1])
+		} else {
+			add(&read[i], nil)
+		}
+	}
+	return spreads
+}
+
+// previewAnnotation is the margin note for one side of a spread
+func previewAnnotation(page *ReadPage, rotations map[int]int) string {
+	if page == nil {
+		return "cover"
+	}
+	note := fmt.Sprintf("%s [%s]", markerName(page.Marker), describeReadPage(*page))
+	if rotation := rotations[page.Marker]; page.Marker > 0 && rotation != 0 {
+		note += fmt.Sprintf(", rotated %d", rotation)
+	}
+	return note
+}
+
+// writeFoldPreview writes the reading-order preview reconstructed from the imposed booklet
+func writeFoldPreview(previewFile, bookletFile string, sidesPerSignature, pagesPerSheet int, direction string, pages []PageInfo) ([]Spread, error) {
+	sides, err := readBookletMarkers(bookletFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	folios := bookletFolios(bookletFile, sides)
+	numberFolios(folios, sidesPerSignature/2, pagesPerSheet)
+	spreads := previewSpreads(foldReadingOrder(folios, sidesPerSignature/2, direction), direction)
+
+	rotations := make(map[int]int, len(pages))
+	for _, page := range pages {
+		rotations[page.Number] = page.Rotate
+	}
+
+	fmt.Printf("Writing fold preview to %s, %d spreads (%s)\n", previewFile, len(spreads), direction)
+	for _, spread := range spreads {
+		fmt.Printf("  Spread %d: %s | %s\n", spread.Number,
+			previewAnnotation(spread.Left, rotations), previewAnnotation(spread.Right, rotations))
+	}
+
+	// In a real implementation, this would place the marked pages side by side and print the notes in the margin
+	return spreads, nil
+}
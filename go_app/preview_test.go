@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviewSpreads(t *testing.T) {
+	read := []ReadPage{{Marker: 1}, {Marker: 2}, {Marker: 3}, {Marker: 4}}
+
+	spreads := previewSpreads(read, "LTR")
+	if len(spreads) != 3 {
+		t.Fatalf("Expected 3 spreads, got %d", len(spreads))
+	}
+	if spreads[0].Left != nil || spreads[0].Right.Marker != 1 {
+		t.Errorf("Expected page 1 alone on the right, got %+v", spreads[0])
+	}
+	if spreads[1].Left.Marker != 2 || spreads[1].Right.Marker != 3 {
+		t.Errorf("Expected pages 2 and 3 facing, got %+v", spreads[1])
+	}
+	if spreads[2].Left.Marker != 4 || spreads[2].Right != nil {
+		t.Errorf("Expected page 4 alone on the left, got %+v", spreads[2])
+	}
+
+	spreads = previewSpreads(read, "RTL")
+	if spreads[0].Left.Marker != 1 || spreads[1].Left.Marker != 3 || spreads[1].Right.Marker != 2 {
+		t.Errorf("Expected mirrored RTL spreads, got %+v", spreads[:2])
+	}
+}
+
+func TestWriteFoldPreview(t *testing.T) {
+	order := []int{0, 0, 1, 2, 3, 4, 5, 0}
+	pages := []PageInfo{{Number: 3, Rotate: 90}}
+
+	bookletFile := filepath.Join(t.TempDir(), "booklet.pdf")
+	if err := writeBookletMarkers(bookletFile, nil, imposeBookletSides(order, 4, "LTR")); err != nil {
+		t.Fatalf("Failed to record page markers: %v", err)
+	}
+
+	spreads, err := writeFoldPreview("preview.pdf", bookletFile, 4, 1, "LTR", pages)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(spreads) != 5 {
+		t.Fatalf("Expected 5 spreads for 8 pages, got %d", len(spreads))
+	}
+
+	// The front blanks come first, then page 1 faces the second blank
+	if spreads[1].Left.Marker != 0 || spreads[1].Right.Marker != 1 {
+		t.Errorf("Expected a blank facing page 1, got %d and %d", spreads[1].Left.Marker, spreads[1].Right.Marker)
+	}
+
+	rotations := map[int]int{3: 90}
+	note := previewAnnotation(spreads[2].Right, rotations)
+	if !strings.Contains(note, "page 3") || !strings.Contains(note, "rotated 90") || !strings.Contains(note, "signature 1") {
+		t.Errorf("Unexpected annotation %q", note)
+	}
+	if previewAnnotation(nil, rotations) != "cover" {
+		t.Error("Expected the inside of the cover to be annotated as cover")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Positions of the stamped marks on a booklet page, in points (see bookit.sh)
+const (
+	stationOffsetY       = 6.0  // Stations are stamped with "offset:x 6" from the bottom edge
+	sectionMarkBase      = 10.0 // Section marks start 10 points from the right edge
+	sectionMarkIncrement = 15.0 // and move 15 points further in for each section
+)
+
+// nupScale returns how much a booklet page shrinks when placed by the n-up layout
+func nupScale(pagesPerSheet int) float64 {
+	if pagesPerSheet <= 1 {
+		return 1
+	}
+	// Each doubling of the n-up halves the area of an A-series page
+	return 1 / math.Sqrt(float64(pagesPerSheet))
+}
+
+// checkPrintableArea returns a warning for every mark or page that lands in the unprintable margin
+//
+// Order places the input pages on the prepared pages, which decides the half
+// of the spread and so the slot edges that lie on the sheet boundary.
+func checkPrintableArea(config *BookletConfig, sheetWidth, sheetHeight float64, totalSides int, order []int, placements []Placement) []string {
+	marginMM := config.Margin
+	if marginMM <= 0 {
+		return nil
+	}
+
+	margin := marginMM * mmToPoints
+	pagesPerSheet, nsections := config.PagesPerSheet, config.Sections
+	scale := nupScale(pagesPerSheet)
+	var warnings []string
+
+	// Stations sit a fixed distance above the bottom edge of the booklet page
+	if !config.NoStations && !config.PerfectBinding && stationOffsetY*scale < margin {
+		warnings = append(warnings, fmt.Sprintf("stations are %.1f mm from the sheet edge, inside the %.1f mm unprintable margin",
+			stationOffsetY*scale/mmToPoints, marginMM))
+	}
+
+	// The first section mark is closest to the right edge, the last one furthest in
+	sectionsCount := 0
+	if nsections > 0 && config.Marks != MarkSpine && !config.PerfectBinding {
+		sectionsCount = int(math.Ceil(float64(totalSides) / float64(nsections*2)))
+	}
+	for section := 1; section <= sectionsCount; section++ {
+		distance := (sectionMarkBase + float64(section-1)*sectionMarkIncrement) * scale
+		if distance < margin {
+			warnings = append(warnings, fmt.Sprintf("section mark %02d is %.1f mm from the sheet edge, inside the %.1f mm unprintable margin",
+				section, distance/mmToPoints, marginMM))
+		}
+	}
+
+	// Only slot edges on the sheet boundary can reach the margin; the spine and the edges between cells are inside the sheet
+	slotWidth, slotHeight := bindingSlotSize(sheetWidth, sheetHeight, pagesPerSheet, config.Binding)
+	if config.PerfectBinding {
+		slotWidth, slotHeight = perfectSlotSize(sheetWidth, sheetHeight, pagesPerSheet)
+	}
+	positions := make(map[int]int, len(order))
+	for i, page := range order {
+		if page > 0 {
+			positions[page] = i + 1
+		}
+	}
+	var clipped []int
+	for _, placement := range placements {
+		edges := [4]bool{true, true, true, true}
+		if position, ok := positions[placement.Page]; ok && !config.PerfectBinding {
+			edges = slotSheetEdges(sheetWidth, sheetHeight, pagesPerSheet, config.Binding, spreadHalf(position, bindingDirection(config.Binding)))
+		}
+		insets := [4]float64{
+			placement.OffsetX,
+			slotWidth - placement.OffsetX - placement.Width,
+			placement.OffsetY,
+			slotHeight - placement.OffsetY - placement.Height,
+		}
+		for i, inset := range insets {
+			if edges[i] && inset < margin {
+				clipped = append(clipped, placement.Page)
+				break
+			}
+		}
+	}
+	if len(clipped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("content of %d pages reaches into the %.1f mm unprintable margin (first: page %d)",
+			len(clipped), marginMM, clipped[0]))
+	}
+
+	for _, warning := range warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	return warnings
+}
+
+// spreadHalf returns the slot of a prepared page in its spread: 1 is the right (or lower) half
+//
+// LTR rectos sit right of the spine, RTL rectos left of it.
+func spreadHalf(position int, direction string) int {
+	if isRecto(position) == (direction == "RTL") {
+		return 0
+	}
+	return 1
+}
+
+// slotSheetEdges reports which edges of a spread half lie on the sheet boundary in at least one n-up cell
+//
+// The edges are left, right, bottom and top, in the orientation of the page.
+func slotSheetEdges(sheetWidth, sheetHeight float64, pagesPerSheet int, binding string, half int) [4]bool {
+	const eps = 0.5
+	width, height := orientSheet(sheetWidth, sheetHeight, sheetIsLandscape(pagesPerSheet, binding))
+	var edges [4]bool
+	for _, cell := range sheetSlots(sheetWidth, sheetHeight, pagesPerSheet, binding) {
+		box := cell[half]
+		edges[0] = edges[0] || box.X < eps
+		edges[1] = edges[1] || box.X+box.Width > width-eps
+		edges[2] = edges[2] || box.Y < eps
+		edges[3] = edges[3] || box.Y+box.Height > height-eps
+	}
+	return edges
+}
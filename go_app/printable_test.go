@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPrintableAreaNoMargin(t *testing.T) {
+	warnings := checkPrintableArea(&BookletConfig{PagesPerSheet: 1, Sections: 8}, a4Width, a4Height, 56, nil, nil)
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings without a margin, got %v", warnings)
+	}
+}
+
+func TestCheckPrintableAreaMarks(t *testing.T) {
+	// 56 sides with 8 sections per signature gives 4 section marks at 10, 25, 40 and 55 points
+	warnings := checkPrintableArea(&BookletConfig{PagesPerSheet: 1, Sections: 8, Margin: 5}, a4Width, a4Height, 56, nil, nil)
+
+	stations, sections := 0, 0
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, "stations") {
+			stations++
+		}
+		if strings.HasPrefix(warning, "section mark") {
+			sections++
+		}
+	}
+	// 5 mm is about 14.2 points
+	if stations != 1 {
+		t.Errorf("Expected a station warning, got %v", warnings)
+	}
+	if sections != 1 {
+		t.Errorf("Expected only section mark 01 inside the margin, got %v", warnings)
+	}
+
+	// Stations left off the sheets can't land in the margin
+	warnings = checkPrintableArea(&BookletConfig{PagesPerSheet: 1, Sections: 8, Margin: 5, NoStations: true}, a4Width, a4Height, 56, nil, nil)
+	for _, warning := range warnings {
+		if strings.HasPrefix(warning, "stations") {
+			t.Errorf("Expected no station warning with NoStations, got %v", warnings)
+		}
+	}
+}
+
+func TestCheckPrintableAreaContent(t *testing.T) {
+	slotW, slotH := slotSize(a4Width, a4Height, 1)
+	pages := []PageInfo{
+		{Number: 1, Width: slotW, Height: slotH},
+		{Number: 2, Width: slotW / 2, Height: slotH / 2},
+	}
+	placements := computePlacements(pages, slotW, slotH, ScaleActual, "c")
+
+	warnings := checkPrintableArea(&BookletConfig{PagesPerSheet: 1, Margin: 3}, a4Width, a4Height, 0, nil, placements)
+	found := false
+	for _, warning := range warnings {
+		if strings.Contains(warning, "content of 1 pages") && strings.Contains(warning, "page 1") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected only the full-slot page 1 to reach into the margin, got %v", warnings)
+	}
+}
+
+func TestCheckPrintableAreaSpineEdge(t *testing.T) {
+	slotW, slotH := slotSize(a4Width, a4Height, 1)
+	// Page 1 touches the left edge of its slot and keeps 20 points clear of the others
+	placements := []Placement{{Page: 1, OffsetX: 0, OffsetY: 20, Width: slotW - 20, Height: slotH - 40}}
+	config := &BookletConfig{PagesPerSheet: 1, Margin: 3, NoStations: true, ReadingDirection: "LTR", Binding: BindingLeft}
+
+	// As an LTR recto it sits right of the spine, so its left edge is the fold
+	if warnings := checkPrintableArea(config, a4Width, a4Height, 0, []int{1, 2}, placements); len(warnings) != 0 {
+		t.Errorf("Expected no warning for content at the spine, got %v", warnings)
+	}
+
+	// As a verso the same edge is the outer edge of the sheet
+	if warnings := checkPrintableArea(config, a4Width, a4Height, 0, []int{0, 1}, placements); len(warnings) != 1 {
+		t.Errorf("Expected a warning for content at the sheet edge, got %v", warnings)
+	}
+
+	// RTL rectos sit left of the spine
+	config.ReadingDirection, config.Binding = "RTL", BindingRight
+	if warnings := checkPrintableArea(config, a4Width, a4Height, 0, []int{1, 2}, placements); len(warnings) != 1 {
+		t.Errorf("Expected a warning for an RTL recto at the sheet edge, got %v", warnings)
+	}
+}
+
+func TestSlotSheetEdges(t *testing.T) {
+	// 4-up: every half has its spine inside, and some cell on each of the three other sheet edges
+	left := slotSheetEdges(a4Width, a4Height, 4, BindingLeft, 0)
+	if left != [4]bool{true, false, true, true} {
+		t.Errorf("Expected the left half on the left, bottom and top edges, got %v", left)
+	}
+	right := slotSheetEdges(a4Width, a4Height, 4, BindingLeft, 1)
+	if right != [4]bool{false, true, true, true} {
+		t.Errorf("Expected the right half on the right, bottom and top edges, got %v", right)
+	}
+}
+
+func TestNupScale(t *testing.T) {
+	if nupScale(1) != 1 || nupScale(4) != 0.5 {
+		t.Errorf("Expected n-up scales 1 and 0.5, got %g and %g", nupScale(1), nupScale(4))
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// defaultConfigFile is the configuration file holding the printer profiles
+const defaultConfigFile = "booklet-maker.json"
+
+// Defaults for the printer-dependent settings when neither a flag nor a profile sets them
+const (
+	DefaultSheet = "A4"
+	DefaultFace  = FaceUp
+)
+
+// sheetSizes maps the supported sheet names to their portrait size in points
+var sheetSizes = map[string][2]float64{
+	"A3":     {841.89, 1190.55},
+	"A4":     {a4Width, a4Height},
+	"A5":     {420.94, 595.28},
+	"Letter": {612, 792},
+	"Legal":  {612, 1008},
+}
+
+// PrinterProfile holds the settings that depend on a physical printer
+type PrinterProfile struct {
+	Sheet       string  `json:"sheet,omitempty"`  // Sheet size name, e.g. "A4" or "Letter"
+	Duplex      string  `json:"duplex,omitempty"` // "manual", "long" or "short" flip edge
+	Face        string  `json:"face,omitempty"`   // "up" or "down" output tray face
+	BackOffsetX float64 `json:"back_offset_x"`    // Back side shift in mm
+	BackOffsetY float64 `json:"back_offset_y"`
+	BackScale   float64 `json:"back_scale,omitempty"` // Back side scale, 0 or 1 for none
+	Margin      float64 `json:"margin,omitempty"`     // Unprintable margin along every edge in mm
+
+	backScaleSet bool // back_scale is in the file, so a 0 there is a mistake rather than unset
+}
+
+// UnmarshalJSON reads a profile, noting whether the file gives a back scale
+func (p *PrinterProfile) UnmarshalJSON(data []byte) error {
+	type plain PrinterProfile
+	var fields struct {
+		plain
+		BackScale *float64 `json:"back_scale"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*p = PrinterProfile(fields.plain)
+	if fields.BackScale != nil {
+		p.BackScale, p.backScaleSet = *fields.BackScale, true
+	}
+	return nil
+}
+
+// sheetSize returns the portrait size of a named sheet in points
+func sheetSize(name string) (float64, float64, error) {
+	size, ok := sheetSizes[name]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown sheet size %q", name)
+	}
+	return size[0], size[1], nil
+}
+
+// Config is the content of the configuration file
+type Config struct {
+	Printers    map[string]PrinterProfile     `json:"printers"`
+	Impositions map[string]ImpositionTemplate `json:"impositions,omitempty"`
+}
+
+// loadConfig reads the configuration file, returning an empty config if it doesn't exist
+func loadConfig(path string) (*Config, error) {
+	config := &Config{Printers: map[string]PrinterProfile{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if config.Printers == nil {
+		config.Printers = map[string]PrinterProfile{}
+	}
+	return config, nil
+}
+
+// saveConfig writes the configuration file
+func saveConfig(path string, config *Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// savePrinterProfile stores a named printer profile in the configuration file
+func savePrinterProfile(path, name string, profile PrinterProfile) error {
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	config.Printers[name] = profile
+	return saveConfig(path, config)
+}
+
+// loadPrinterProfile returns a named printer profile from the configuration file
+func loadPrinterProfile(path, name string) (PrinterProfile, error) {
+	config, err := loadConfig(path)
+	if err != nil {
+		return PrinterProfile{}, err
+	}
+	profile, ok := config.Printers[name]
+	if !ok {
+		return PrinterProfile{}, fmt.Errorf("printer profile %q not found in %s", name, path)
+	}
+	return profile, nil
+}
+
+// applyPrinterProfile fills the printer-dependent settings the user left unset
+func applyPrinterProfile(config *BookletConfig, profile PrinterProfile) {
+	if config.Sheet == "" {
+		config.Sheet = profile.Sheet
+	}
+	if config.Duplex == "" {
+		config.Duplex = profile.Duplex
+	}
+	if config.Face == "" {
+		config.Face = profile.Face
+	}
+	if !config.BackOffsetSet {
+		config.BackOffsetX, config.BackOffsetY = profile.BackOffsetX, profile.BackOffsetY
+	}
+	if !config.BackScaleSet {
+		config.BackScale = profile.BackScale
+	}
+	if !config.MarginSet {
+		config.Margin = profile.Margin
+	}
+}
+
+// validatePrinterProfile checks the profile's settings the same way as the command line flags
+func validatePrinterProfile(name string, profile PrinterProfile) error {
+	if profile.Duplex != "" && !isValidOption(profile.Duplex, validDuplexModes) {
+		return fmt.Errorf("printer profile %q: duplex must be manual, long, or short, got %s", name, profile.Duplex)
+	}
+	if profile.Face != "" && !isValidOption(profile.Face, validFaces) {
+		return fmt.Errorf("printer profile %q: face must be up or down, got %s", name, profile.Face)
+	}
+	if profile.Sheet != "" {
+		if _, _, err := sheetSize(profile.Sheet); err != nil {
+			return fmt.Errorf("printer profile %q: %w", name, err)
+		}
+	}
+	if profile.BackScale < 0 || (profile.backScaleSet && profile.BackScale == 0) {
+		return fmt.Errorf("printer profile %q: back scale must be positive, got %g", name, profile.BackScale)
+	}
+	if profile.Margin < 0 {
+		return fmt.Errorf("printer profile %q: margin must not be negative, got %g", name, profile.Margin)
+	}
+	return nil
+}
+
+// applyPrinterDefaults fills the printer-dependent settings still unset after the profile
+func applyPrinterDefaults(config *BookletConfig) {
+	if config.Sheet == "" {
+		config.Sheet = DefaultSheet
+	}
+	if config.Duplex == "" {
+		config.Duplex = DuplexManual
+	}
+	if config.Face == "" {
+		config.Face = DefaultFace
+	}
+	if config.BackScale == 0 {
+		config.BackScale = 1
+	}
+}
+
+// loadPrinterSettings applies the named printer profile and the defaults to the config
+func loadPrinterSettings(config *BookletConfig) error {
+	if config.Printer != "" {
+		configFile := config.ConfigFile
+		if configFile == "" {
+			configFile = defaultConfigFile
+		}
+		profile, err := loadPrinterProfile(configFile, config.Printer)
+		if err == nil {
+			err = validatePrinterProfile(config.Printer, profile)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Using printer profile %q from %s\n", config.Printer, configFile)
+		applyPrinterProfile(config, profile)
+	}
+	applyPrinterDefaults(config)
+
+	_, _, err := sheetSize(config.Sheet)
+	return err
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	config, err := loadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for a missing config file, got %v", err)
+	}
+	if len(config.Printers) != 0 {
+		t.Errorf("Expected no printer profiles, got %d", len(config.Printers))
+	}
+}
+
+func TestSaveAndLoadPrinterProfile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+
+	err := savePrinterProfile(configFile, "office", PrinterProfile{BackOffsetX: 1.5, BackOffsetY: -0.5})
+	if err != nil {
+		t.Fatalf("Failed to save printer profile: %v", err)
+	}
+	err = savePrinterProfile(configFile, "home", PrinterProfile{BackScale: 0.998})
+	if err != nil {
+		t.Fatalf("Failed to save second printer profile: %v", err)
+	}
+
+	profile, err := loadPrinterProfile(configFile, "office")
+	if err != nil {
+		t.Fatalf("Failed to load printer profile: %v", err)
+	}
+	if profile.BackOffsetX != 1.5 || profile.BackOffsetY != -0.5 {
+		t.Errorf("Expected back offset 1.5,-0.5, got %g,%g", profile.BackOffsetX, profile.BackOffsetY)
+	}
+
+	if _, err := loadPrinterProfile(configFile, "missing"); err == nil {
+		t.Error("Expected error for a missing printer profile, got nil")
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	if err := os.WriteFile(configFile, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	if _, err := loadConfig(configFile); err == nil {
+		t.Error("Expected error for an invalid config file, got nil")
+	}
+}
+
+func TestApplyPrinterProfile(t *testing.T) {
+	profile := PrinterProfile{Sheet: "Letter", Duplex: DuplexLong, Face: FaceDown, BackOffsetX: 1.5, Margin: 4}
+
+	// Settings given on the command line win over the profile
+	config := &BookletConfig{Duplex: DuplexShort}
+	applyPrinterProfile(config, profile)
+	applyPrinterDefaults(config)
+
+	if config.Duplex != DuplexShort {
+		t.Errorf("Expected explicit duplex mode to be kept, got %s", config.Duplex)
+	}
+	if config.Sheet != "Letter" || config.Face != FaceDown || config.BackOffsetX != 1.5 || config.Margin != 4 {
+		t.Errorf("Expected profile settings to be applied, got %+v", config)
+	}
+	if config.BackScale != 1 {
+		t.Errorf("Expected default back scale 1, got %g", config.BackScale)
+	}
+
+	// An explicit -back-offset 0,0 also wins over the profile
+	config = &BookletConfig{BackOffsetSet: true}
+	applyPrinterProfile(config, profile)
+	if config.BackOffsetX != 0 || config.BackOffsetY != 0 {
+		t.Errorf("Expected the explicit 0,0 back offset to be kept, got %g,%g", config.BackOffsetX, config.BackOffsetY)
+	}
+
+	// So do an explicit -margin 0 and -back-scale
+	config = &BookletConfig{MarginSet: true, BackScale: 1, BackScaleSet: true}
+	applyPrinterProfile(config, PrinterProfile{Margin: 4, BackScale: 0.998})
+	if config.Margin != 0 || config.BackScale != 1 {
+		t.Errorf("Expected the explicit margin 0 and back scale 1 to be kept, got %g and %g", config.Margin, config.BackScale)
+	}
+
+	// Without a profile the defaults apply
+	config = &BookletConfig{}
+	applyPrinterDefaults(config)
+	if config.Sheet != DefaultSheet || config.Duplex != DuplexManual || config.Face != FaceUp {
+		t.Errorf("Expected default printer settings, got %+v", config)
+	}
+}
+
+func TestLoadPrinterSettings(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	err := savePrinterProfile(configFile, "office", PrinterProfile{Sheet: "A3", Duplex: DuplexShort})
+	if err != nil {
+		t.Fatalf("Failed to save printer profile: %v", err)
+	}
+
+	config := &BookletConfig{Printer: "office", ConfigFile: configFile}
+	if err := loadPrinterSettings(config); err != nil {
+		t.Fatalf("Failed to load printer settings: %v", err)
+	}
+	if config.Sheet != "A3" || config.Duplex != DuplexShort {
+		t.Errorf("Expected A3 short-edge duplex from the profile, got %+v", config)
+	}
+
+	config = &BookletConfig{Printer: "missing", ConfigFile: configFile}
+	if err := loadPrinterSettings(config); err == nil {
+		t.Error("Expected error for a missing printer profile, got nil")
+	}
+
+	for name, profile := range map[string]PrinterProfile{
+		"typo":  {Duplex: "lng"},
+		"tray":  {Face: "sideways"},
+		"paper": {Sheet: "B5"},
+		"scale": {BackScale: -1},
+		"edge":  {Margin: -2},
+	} {
+		if err := savePrinterProfile(configFile, name, profile); err != nil {
+			t.Fatalf("Failed to save printer profile: %v", err)
+		}
+		config = &BookletConfig{Printer: name, ConfigFile: configFile}
+		if err := loadPrinterSettings(config); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected error naming the invalid profile %q, got %v", name, err)
+		}
+	}
+
+	// A back scale of 0 written in the file is a mistake, not the default
+	zeroFile := filepath.Join(t.TempDir(), "booklet-maker.json")
+	if err := os.WriteFile(zeroFile, []byte(`{"printers": {"office": {"back_scale": 0}}}`), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	config = &BookletConfig{Printer: "office", ConfigFile: zeroFile}
+	if err := loadPrinterSettings(config); err == nil || !strings.Contains(err.Error(), "back scale") {
+		t.Errorf("Expected error about the zero back scale, got %v", err)
+	}
+
+	config = &BookletConfig{Sheet: "B5"}
+	if err := loadPrinterSettings(config); err == nil {
+		t.Error("Expected error for an unknown sheet size, got nil")
+	}
+}
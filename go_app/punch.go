@@ -0,0 +1,108 @@
+package main
+
+import "fmt"
+
+// Kinds of sewing stations on the punching template
+const (
+	StationKettle = "kettle" // Outermost stations, where the kettle stitch links the signatures
+	StationTape   = "tape"   // Inner stations, paired around a tape or cord
+)
+
+// PunchStation is one hole position on the punching template
+type PunchStation struct {
+	Number     int
+	Percent    float64 // Position along the spine from the head, as in addStations
+	FromHeadMM float64 // Distance from the head of the fold in mm
+	Kind       string
+	Pair       int // Tape pair number for tape stations, 0 for kettle stitches
+}
+
+// TapePair is the spacing of two tape stations
+type TapePair struct {
+	Pair    int
+	StartMM float64
+	EndMM   float64
+	WidthMM float64
+}
+
+// punchStations computes the template stations from the same percentages as addStations
+//
+// The first and last stations are kettle stitches; the stations in between
+// are taken two by two as tape pairs.
+func punchStations(pagesPerSheet int, spineLength float64) []PunchStation {
+	percentages := stationPercentages(pagesPerSheet)
+	spineMM := spineLength / mmToPoints
+
+	stations := make([]PunchStation, len(percentages))
+	for i, percent := range percentages {
+		station := PunchStation{
+			Number:     i + 1,
+			Percent:    percent,
+			FromHeadMM: percent / 100 * spineMM,
+			Kind:       StationTape,
+			Pair:       (i + 1) / 2,
+		}
+		if i == 0 || i == len(percentages)-1 {
+			station.Kind = StationKettle
+			station.Pair = 0
+		}
+		stations[i] = station
+	}
+	return stations
+}
+
+// tapePairs returns the spacing of every tape pair on the template
+func tapePairs(stations []PunchStation) []TapePair {
+	var pairs []TapePair
+	for i := 0; i+1 < len(stations); i++ {
+		first, second := stations[i], stations[i+1]
+		if first.Kind != StationTape || first.Pair != second.Pair {
+			continue
+		}
+		pairs = append(pairs, TapePair{
+			Pair:    first.Pair,
+			StartMM: first.FromHeadMM,
+			EndMM:   second.FromHeadMM,
+			WidthMM: second.FromHeadMM - first.FromHeadMM,
+		})
+		i++
+	}
+	return pairs
+}
+
+// writePunchTemplate writes the single-page punching template PDF for the -pages layout
+func writePunchTemplate(templateFile string, sheetWidth, sheetHeight float64, pagesPerSheet int, binding string) []PunchStation {
+	fold := "spine"
+	if !foldIsVertical(binding) {
+		fold = "top"
+	}
+	return writePunchStations(templateFile, fold, foldLength(sheetWidth, sheetHeight, pagesPerSheet, binding), pagesPerSheet)
+}
+
+// writeFoldPunchTemplate writes the punching template for the final fold of a folded imposition template
+//
+// The stations match the dots addFoldMarks stamps on the final fold, which
+// runs across the template sheet rather than along the -pages spine.
+func writeFoldPunchTemplate(templateFile string, t ImpositionTemplate, sheetWidth, sheetHeight float64) ([]PunchStation, error) {
+	fold, err := finalFold(t, sheetWidth, sheetHeight)
+	if err != nil {
+		return nil, err
+	}
+	return writePunchStations(templateFile, t.Name+" final", fold.Top-fold.Bottom, t.Columns*t.Rows/2), nil
+}
+
+// writePunchStations writes the template holes along one fold
+func writePunchStations(templateFile, fold string, spineLength float64, pagesPerSheet int) []PunchStation {
+	stations := punchStations(pagesPerSheet, spineLength)
+
+	fmt.Printf("Writing punching template to %s, %s fold %.1f mm\n", templateFile, fold, spineLength/mmToPoints)
+	for _, station := range stations {
+		fmt.Printf("  Station %d (%s): %.1f mm from head (%.1f%%)\n", station.Number, station.Kind, station.FromHeadMM, station.Percent)
+	}
+	for _, pair := range tapePairs(stations) {
+		fmt.Printf("  Tape pair %d: %.1f-%.1f mm, spacing %.1f mm\n", pair.Pair, pair.StartMM, pair.EndMM, pair.WidthMM)
+	}
+
+	// In a real implementation, this would draw the fold line, holes and measurements on one page
+	return stations
+}
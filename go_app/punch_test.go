@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPunchStations(t *testing.T) {
+	// A5 spine of 210 mm for the 1-up layout
+	stations := punchStations(1, 210*mmToPoints)
+	if len(stations) != 8 {
+		t.Fatalf("Expected 8 stations for 1-up, got %d", len(stations))
+	}
+
+	if stations[0].Kind != StationKettle || stations[7].Kind != StationKettle {
+		t.Errorf("Expected the outer stations to be kettle stitches, got %s and %s", stations[0].Kind, stations[7].Kind)
+	}
+	if math.Abs(stations[0].FromHeadMM-14.7) > 0.01 {
+		t.Errorf("Expected first station 14.7 mm from the head, got %.2f", stations[0].FromHeadMM)
+	}
+	for _, station := range stations[1:7] {
+		if station.Kind != StationTape || station.Pair == 0 {
+			t.Errorf("Expected station %d to be part of a tape pair, got %+v", station.Number, station)
+		}
+	}
+}
+
+func TestTapePairs(t *testing.T) {
+	testCases := []struct {
+		pagesPerSheet int
+		expectedPairs int
+	}{
+		{1, 3},
+		{2, 2},
+		{4, 1},
+	}
+
+	for _, tc := range testCases {
+		pairs := tapePairs(punchStations(tc.pagesPerSheet, 100*mmToPoints))
+		if len(pairs) != tc.expectedPairs {
+			t.Errorf("For PagesPerSheet=%d, expected %d tape pairs, got %d", tc.pagesPerSheet, tc.expectedPairs, len(pairs))
+		}
+	}
+
+	// The 4-up tape pair spans 36.6% to 63.3% of a 100 mm spine
+	pairs := tapePairs(punchStations(4, 100*mmToPoints))
+	if math.Abs(pairs[0].WidthMM-26.7) > 0.01 {
+		t.Errorf("Expected tape spacing 26.7 mm, got %.2f", pairs[0].WidthMM)
+	}
+}
+
+func TestFoldPunchTemplate(t *testing.T) {
+	for _, name := range []string{"quarto", "octavo"} {
+		template := builtinImpositions[name]
+		stations, err := writeFoldPunchTemplate("punch.pdf", template, a4Width, a4Height)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		// The holes must meet the dots stamped on the final fold
+		fold, _ := finalFold(template, a4Width, a4Height)
+		dots := foldStations(fold, template.Columns*template.Rows/2)
+		if len(stations) != len(dots) {
+			t.Fatalf("%s: expected %d stations, got %d", name, len(dots), len(stations))
+		}
+		for i, station := range stations {
+			if math.Abs(fold.Top-station.FromHeadMM*mmToPoints-dots[i][1]) > 0.01 {
+				t.Errorf("%s: station %d at %.1f mm from the head misses the dot at %.1f points", name, station.Number, station.FromHeadMM, dots[i][1])
+			}
+		}
+	}
+
+}
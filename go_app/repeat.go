@@ -0,0 +1,56 @@
+package main
+
+import "fmt"
+
+// validateRepeat checks that the layout has n-up cells to repeat the booklet in
+func validateRepeat(pagesPerSheet int, imposition string, cutStack bool) error {
+	if imposition != "" {
+		return fmt.Errorf("repeat can't be combined with an imposition template")
+	}
+	if cutStack {
+		return fmt.Errorf("repeat can't be combined with cut-and-stack, every cut stack is already a whole copy")
+	}
+	if pagesPerSheet != 2 && pagesPerSheet != 4 && pagesPerSheet != 8 {
+		return fmt.Errorf("repeat needs 2, 4, or 8 pages per sheet, got %d", pagesPerSheet)
+	}
+	return nil
+}
+
+// repeatSheets places one folio per physical sheet, repeated in every n-up cell
+//
+// The back carries the same booklet page in every cell, so it registers with
+// the front whichever way the sheet is flipped, and each cut stack is a whole
+// copy of the booklet.
+func repeatSheets(firstPage, sides, pagesPerSheet int) ([]PrintSide, []PrintSide) {
+	var fronts, backs []PrintSide
+	for i := 0; i < sides; i += 2 {
+		sheet := len(fronts) + 1
+		front := PrintSide{Sheet: sheet}
+		back := PrintSide{Sheet: sheet, Back: true}
+		for cell := 0; cell < pagesPerSheet; cell++ {
+			front.Pages = append(front.Pages, firstPage+i)
+			if i+1 < sides {
+				back.Pages = append(back.Pages, firstPage+i+1)
+			}
+		}
+		fronts = append(fronts, front)
+		backs = append(backs, back)
+	}
+	return fronts, backs
+}
+
+// repeatProblems reports the cells of a repeated layout that don't show the same page as the first cell
+func repeatProblems(files []MarkedFile) []string {
+	var problems []string
+	for _, file := range files {
+		for i, side := range file.Sides {
+			for cell := 1; cell < len(side); cell++ {
+				if side[cell] != side[0] {
+					problems = append(problems, fmt.Sprintf("%s side %d cell %d shows pages %v, cell 1 shows %v",
+						file.Name, i+1, cell+1, side[cell], side[0]))
+				}
+			}
+		}
+	}
+	return problems
+}
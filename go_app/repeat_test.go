@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepeatSheets(t *testing.T) {
+	fronts, backs := repeatSheets(5, 4, 4)
+	if len(fronts) != 2 || len(backs) != 2 {
+		t.Fatalf("Expected one sheet per folio, got %d fronts and %d backs", len(fronts), len(backs))
+	}
+	if !reflect.DeepEqual(fronts[1].Pages, []int{7, 7, 7, 7}) || !reflect.DeepEqual(backs[1].Pages, []int{8, 8, 8, 8}) {
+		t.Errorf("Expected page 7 and 8 in every cell of sheet 2, got %v / %v", fronts[1].Pages, backs[1].Pages)
+	}
+	if fronts[1].Sheet != 2 || !backs[1].Back {
+		t.Errorf("Expected sheet 2 front and back, got %+v and %+v", fronts[1], backs[1])
+	}
+}
+
+func TestPrintedFoliosRepeat(t *testing.T) {
+	order := sequentialOrder(40)
+	sides := imposeBookletSides(order, 16, "RTL")
+
+	for _, pagesPerSheet := range []int{2, 4, 8} {
+		for _, opts := range []PrintOptions{
+			{Mode: DuplexManual, Face: FaceUp, Repeat: true},
+			{Mode: DuplexManual, Face: FaceDown, Repeat: true},
+			{Mode: DuplexLong, Scope: DuplexPerSignature, Repeat: true},
+			{Mode: DuplexShort, Scope: DuplexPerBook, Repeat: true},
+		} {
+			files := markPrintFiles("print_ready", planPrintFiles("booklet.pdf", len(sides), 16, pagesPerSheet, opts), sides)
+			if problems := repeatProblems(files); len(problems) > 0 {
+				t.Errorf("%d-up %+v: unexpected cell problems %v", pagesPerSheet, opts, problems)
+			}
+			folios := printedFolios(files, opts)
+			numberFolios(folios, 8, 1)
+			read := foldReadingOrder(folios, 8, "RTL")
+			if problems := checkReadingOrder(read, order); len(problems) > 0 {
+				t.Errorf("%d-up %+v: unexpected problems %v", pagesPerSheet, opts, problems)
+			}
+		}
+	}
+}
+
+func TestRepeatProblems(t *testing.T) {
+	files := []MarkedFile{{Name: "1_B_book_1.pdf", Sides: [][][2]int{{{2, 7}, {2, 7}, {7, 2}, {2, 7}}}}}
+	problems := repeatProblems(files)
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 problem, got %v", problems)
+	}
+	if !strings.Contains(problems[0], "side 1 cell 3") {
+		t.Errorf("Expected the problem to name the side and cell, got %q", problems[0])
+	}
+}
+
+func TestValidateRepeat(t *testing.T) {
+	if err := validateRepeat(4, "", false); err != nil {
+		t.Errorf("Expected 4-up to be valid, got %v", err)
+	}
+	if err := validateRepeat(1, "", false); err == nil {
+		t.Errorf("Expected an error for 1 page per sheet")
+	}
+	if err := validateRepeat(4, "", true); err == nil || !strings.Contains(err.Error(), "cut-and-stack") {
+		t.Errorf("Expected an error with cut-and-stack, got %v", err)
+	}
+	if err := validateRepeat(2, "octavo", false); err == nil {
+		t.Errorf("Expected an error with an imposition template")
+	}
+}
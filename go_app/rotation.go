@@ -0,0 +1,94 @@
+package main
+
+import "fmt"
+
+// Auto-rotation modes for landscape pages
+const (
+	RotateOff = "off" // Leave landscape pages as they are
+	RotateCW  = "cw"  // Turn landscape rectos clockwise, the head of the page faces the outer edge
+	RotateCCW = "ccw" // Turn landscape rectos counter-clockwise, the foot of the page faces the outer edge
+)
+
+// validRotateModes lists the accepted values for BookletConfig.Rotate
+var validRotateModes = []string{RotateOff, RotateCW, RotateCCW}
+
+// effectiveSize returns the displayed size of a page, taking /Rotate into account
+func effectiveSize(page PageInfo) (float64, float64) {
+	if normalizeRotation(page.Rotate)%180 == 90 {
+		return page.Height, page.Width
+	}
+	return page.Width, page.Height
+}
+
+// isLandscape reports whether a page is displayed wider than it is tall
+func isLandscape(page PageInfo) bool {
+	w, h := effectiveSize(page)
+	return w > h
+}
+
+// normalizeRotation maps any multiple of 90 degrees into the range 0-270
+func normalizeRotation(rotate int) int {
+	rotate %= 360
+	if rotate < 0 {
+		rotate += 360
+	}
+	return rotate
+}
+
+// isRecto reports whether a page position in reading order falls on a recto
+func isRecto(position int) bool {
+	return position%2 == 1
+}
+
+// rotationFor returns the extra rotation for a landscape page so it fills a portrait slot
+//
+// Rectos and versos turn in opposite directions, so the plate keeps the same
+// relation to the outer edge on both sides of a leaf. RTL books mirror the
+// spread, which moves the outer edge of a recto to the left.
+func rotationFor(position int, mode, direction string) int {
+	angle := 90
+	if mode == RotateCCW {
+		angle = -90
+	}
+	if !isRecto(position) {
+		angle = -angle
+	}
+	if direction == "RTL" {
+		angle = -angle
+	}
+	return angle
+}
+
+// applyRotation turns landscape pages into portrait slots, or portrait pages into top-bound landscape slots
+//
+// frontBlanks is the number of blank pages inserted before the first input
+// page, which decides whether a page ends up on a recto or a verso.
+func applyRotation(pages []PageInfo, mode, direction, binding string, frontBlanks int) []PageInfo {
+	if mode == "" || mode == RotateOff {
+		return pages
+	}
+
+	rotated := make([]PageInfo, len(pages))
+	copy(rotated, pages)
+	for i, page := range rotated {
+		if fitsSlot(page, binding) {
+			continue
+		}
+		position := page.Number + frontBlanks
+		angle := rotationFor(position, mode, direction)
+		rotated[i].Rotate = normalizeRotation(page.Rotate + angle)
+
+		side := "verso"
+		if isRecto(position) {
+			side = "recto"
+		}
+		orientation := "landscape"
+		if !isLandscape(page) {
+			orientation = "portrait"
+		}
+		fmt.Printf("  Rotating %s page %d (%s) by %d degrees\n", orientation, page.Number, side, angle)
+	}
+
+	// In a real implementation, this would update the /Rotate attribute of each page
+	return rotated
+}
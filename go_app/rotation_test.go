@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+func TestEffectiveSize(t *testing.T) {
+	page := PageInfo{Number: 1, Width: 400, Height: 600, Rotate: 90}
+	w, h := effectiveSize(page)
+	if w != 600 || h != 400 {
+		t.Errorf("Expected /Rotate 90 to swap the size to 600x400, got %.0fx%.0f", w, h)
+	}
+
+	if !isLandscape(page) {
+		t.Error("Portrait media box with /Rotate 90 should be landscape")
+	}
+
+	page.Rotate = -180
+	if isLandscape(page) {
+		t.Error("Portrait media box with /Rotate -180 should stay portrait")
+	}
+}
+
+func TestRotationFor(t *testing.T) {
+	testCases := []struct {
+		position  int
+		mode      string
+		direction string
+		expected  int
+	}{
+		{1, RotateCW, "LTR", 90},
+		{2, RotateCW, "LTR", -90},
+		{1, RotateCCW, "LTR", -90},
+		{2, RotateCCW, "LTR", 90},
+		{1, RotateCW, "RTL", -90},
+		{2, RotateCW, "RTL", 90},
+	}
+
+	for _, tc := range testCases {
+		angle := rotationFor(tc.position, tc.mode, tc.direction)
+		if angle != tc.expected {
+			t.Errorf("For position=%d, mode=%s, direction=%s, expected %d, got %d",
+				tc.position, tc.mode, tc.direction, tc.expected, angle)
+		}
+	}
+}
+
+func TestApplyRotation(t *testing.T) {
+	pages := []PageInfo{
+		{Number: 1, Width: 400, Height: 600},
+		{Number: 2, Width: 600, Height: 400},
+		{Number: 3, Width: 600, Height: 400},
+		{Number: 4, Width: 400, Height: 600, Rotate: 270},
+	}
+
+	rotated := applyRotation(pages, RotateCW, "LTR", BindingLeft, 2)
+
+	if rotated[0].Rotate != 0 {
+		t.Errorf("Portrait page 1 should not be rotated, got %d", rotated[0].Rotate)
+	}
+	// Page 2 lands on position 4, a verso
+	if rotated[1].Rotate != 270 {
+		t.Errorf("Expected verso page 2 to rotate to 270, got %d", rotated[1].Rotate)
+	}
+	// Page 3 lands on position 5, a recto
+	if rotated[2].Rotate != 90 {
+		t.Errorf("Expected recto page 3 to rotate to 90, got %d", rotated[2].Rotate)
+	}
+	// Page 4 is landscape through /Rotate 270 and lands on a verso
+	if rotated[3].Rotate != 180 {
+		t.Errorf("Expected page 4 to rotate from 270 to 180, got %d", rotated[3].Rotate)
+	}
+	for _, page := range rotated {
+		if isLandscape(page) {
+			t.Errorf("Page %d should be portrait after rotation", page.Number)
+		}
+	}
+
+	if pages[1].Rotate != 0 {
+		t.Error("applyRotation should not modify the input pages")
+	}
+
+	off := applyRotation(pages, RotateOff, "LTR", BindingLeft, 2)
+	if off[1].Rotate != 0 {
+		t.Errorf("Rotation off should leave page 2 unchanged, got %d", off[1].Rotate)
+	}
+}
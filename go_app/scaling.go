@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scaling modes for placing source pages into their slot
+const (
+	ScaleFit     = "fit"     // Scale down or up so the whole page fits the slot
+	ScaleFill    = "fill"    // Scale so the page covers the slot, cropping the overflow
+	ScaleActual  = "actual"  // Keep the page at 100%, cropping or padding as needed
+	ScaleFirst   = "first"   // Normalize every page to the size of the first page
+	ScaleLargest = "largest" // Normalize every page to the size of the largest page
+)
+
+// DefaultAlign centers a page in its slot
+const DefaultAlign = "c"
+
+// sizeTolerance is the size difference in points below which pages count as equal
+const sizeTolerance = 1.0
+
+// A4 sheet size in points, the default sheet of the n-up layouts
+const (
+	a4Width  = 595.28
+	a4Height = 841.89
+)
+
+// validScaleModes lists the accepted values for BookletConfig.Scale
+var validScaleModes = []string{ScaleFit, ScaleFill, ScaleActual, ScaleFirst, ScaleLargest}
+
+// validAlignments lists the accepted anchors for BookletConfig.Align (pdfcpu style)
+var validAlignments = []string{"c", "tl", "tc", "tr", "l", "r", "bl", "bc", "br"}
+
+// PageInfo describes a single page of the input PDF
+type PageInfo struct {
+	Number int
+	Width  float64
+	Height float64
+	Rotate int // Value of the /Rotate attribute (0, 90, 180 or 270)
+}
+
+// Placement describes how a source page is drawn into its slot
+type Placement struct {
+	Page    int
+	Scale   float64
+	OffsetX float64 // Offset of the scaled page from the slot's lower left corner
+	OffsetY float64
+	Width   float64 // Size of the scaled page
+	Height  float64
+	Cropped bool // The scaled page extends past the slot
+}
+
+// readPageInfo reads the page sizes of the input PDF
+func readPageInfo(inputFile string) ([]PageInfo, error) {
+	// In a real implementation, this would read the media boxes from the PDF
+	totalPages := 100 // Simulated total pages
+	pages := make([]PageInfo, totalPages)
+	for i := range pages {
+		pages[i] = PageInfo{Number: i + 1, Width: 420.94, Height: 595.28}
+	}
+	return pages, nil
+}
+
+// slotSize returns the portrait size of one page slot on the sheet for the given layout
+func slotSize(sheetWidth, sheetHeight float64, pagesPerSheet int) (float64, float64) {
+	// Each sheet side holds two booklet pages per sheet unit, halving the long side each time
+	w, h := sheetWidth, sheetHeight
+	for slots := pagesPerSheet * 2; slots > 1; slots /= 2 {
+		if w > h {
+			w /= 2
+		} else {
+			h /= 2
+		}
+	}
+	return math.Min(w, h), math.Max(w, h)
+}
+
+// isValidOption reports whether value is one of the allowed options
+func isValidOption(value string, options []string) bool {
+	for _, option := range options {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// referenceSize returns the size every page is normalized to for the first/largest modes
+func referenceSize(pages []PageInfo, mode string) (float64, float64) {
+	if len(pages) == 0 {
+		return 0, 0
+	}
+	w, h := effectiveSize(pages[0])
+	if mode == ScaleFirst {
+		return w, h
+	}
+	for _, page := range pages[1:] {
+		pw, ph := effectiveSize(page)
+		if pw*ph > w*h {
+			w, h = pw, ph
+		}
+	}
+	return w, h
+}
+
+// computePlacements calculates the scale and offset of every page in its slot
+func computePlacements(pages []PageInfo, slotWidth, slotHeight float64, mode, align string) []Placement {
+	refWidth, refHeight := referenceSize(pages, mode)
+
+	placements := make([]Placement, 0, len(pages))
+	for _, page := range pages {
+		if page.Width <= 0 || page.Height <= 0 {
+			continue
+		}
+
+		pw, ph := effectiveSize(page)
+
+		var scale float64
+		switch mode {
+		case ScaleFill:
+			scale = math.Max(slotWidth/pw, slotHeight/ph)
+		case ScaleActual:
+			scale = 1
+		case ScaleFirst, ScaleLargest:
+			// Fit the page into the reference size, then fit the reference into the slot
+			toRef := math.Min(refWidth/pw, refHeight/ph)
+			toSlot := math.Min(slotWidth/refWidth, slotHeight/refHeight)
+			scale = toRef * toSlot
+		default:
+			scale = math.Min(slotWidth/pw, slotHeight/ph)
+		}
+
+		w, h := pw*scale, ph*scale
+		x, y := alignOffset(slotWidth-w, slotHeight-h, align)
+		placements = append(placements, Placement{
+			Page:    page.Number,
+			Scale:   scale,
+			OffsetX: x,
+			OffsetY: y,
+			Width:   w,
+			Height:  h,
+			Cropped: w-slotWidth > sizeTolerance || h-slotHeight > sizeTolerance,
+		})
+	}
+
+	return placements
+}
+
+// alignOffset distributes the free space of a slot according to the anchor
+func alignOffset(freeX, freeY float64, align string) (float64, float64) {
+	x, y := freeX/2, freeY/2
+	switch align {
+	case "tl", "l", "bl":
+		x = 0
+	case "tr", "r", "br":
+		x = freeX
+	}
+	switch align {
+	case "tl", "tc", "tr":
+		y = freeY
+	case "bl", "bc", "br":
+		y = 0
+	}
+	return x, y
+}
+
+// majoritySize returns the most common displayed page size, ignoring small differences
+//
+// Pages with a 90 or 270 degree /Rotate count with their width and height
+// swapped, as they are shown.
+func majoritySize(pages []PageInfo) (float64, float64) {
+	var bestWidth, bestHeight float64
+	bestCount := 0
+	for _, candidate := range pages {
+		width, height := effectiveSize(candidate)
+		count := 0
+		for _, page := range pages {
+			if sameSize(page, width, height) {
+				count++
+			}
+		}
+		if count > bestCount {
+			bestCount = count
+			bestWidth, bestHeight = width, height
+		}
+	}
+	return bestWidth, bestHeight
+}
+
+// sameSize reports whether a page is displayed at the given size within sizeTolerance
+func sameSize(page PageInfo, width, height float64) bool {
+	pageWidth, pageHeight := effectiveSize(page)
+	return math.Abs(pageWidth-width) <= sizeTolerance && math.Abs(pageHeight-height) <= sizeTolerance
+}
+
+// oddSizedPages returns the pages whose size differs from the majority
+func oddSizedPages(pages []PageInfo) []PageInfo {
+	width, height := majoritySize(pages)
+	var odd []PageInfo
+	for _, page := range pages {
+		if !sameSize(page, width, height) {
+			odd = append(odd, page)
+		}
+	}
+	return odd
+}
+
+// applyScaling reports the scaling policy and the pages that differ in size
+func applyScaling(inputFile string, pages []PageInfo, sheetWidth, sheetHeight float64, pagesPerSheet int, binding, mode, align string) []Placement {
+	slotWidth, slotHeight := bindingSlotSize(sheetWidth, sheetHeight, pagesPerSheet, binding)
+	return reportScaling(inputFile, pages, slotWidth, slotHeight, mode, align)
+}
+
+// pageSizeLine describes a page by its displayed size, as the majority size is counted
+func pageSizeLine(page PageInfo) string {
+	width, height := effectiveSize(page)
+	line := fmt.Sprintf("page %d: %.2fx%.2f", page.Number, width, height)
+	if rotation := normalizeRotation(page.Rotate); rotation != 0 {
+		line += fmt.Sprintf(" (%.2fx%.2f with /Rotate %d)", page.Width, page.Height, rotation)
+	}
+	return line
+}
+
+// reportScaling computes the placements in one slot size and reports the odd-sized and cropped pages
+func reportScaling(inputFile string, pages []PageInfo, slotWidth, slotHeight float64, mode, align string) []Placement {
+	fmt.Printf("Scaling pages of %s: mode=%s, align=%s, slot=%.2fx%.2f\n", inputFile, mode, align, slotWidth, slotHeight)
+
+	width, height := majoritySize(pages)
+	odd := oddSizedPages(pages)
+	if len(odd) == 0 {
+		fmt.Printf("  All %d pages are %.2fx%.2f\n", len(pages), width, height)
+	} else {
+		fmt.Printf("  %d of %d pages differ from the majority size %.2fx%.2f:\n", len(odd), len(pages), width, height)
+		for _, page := range odd {
+			fmt.Printf("    %s\n", pageSizeLine(page))
+		}
+	}
+
+	placements := computePlacements(pages, slotWidth, slotHeight, mode, align)
+	for _, placement := range placements {
+		if placement.Cropped {
+			fmt.Printf("  Page %d will be cropped at scale %.3f\n", placement.Page, placement.Scale)
+		}
+	}
+
+	// In a real implementation, the placements would be used when creating the booklet layout
+	return placements
+}
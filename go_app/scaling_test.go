@@ -0,0 +1,145 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSlotSize(t *testing.T) {
+	testCases := []struct {
+		pagesPerSheet int
+		width, height float64
+	}{
+		{1, 420.945, 595.28},
+		{2, 297.64, 420.945},
+		{4, 210.4725, 297.64},
+		{8, 148.82, 210.4725},
+	}
+
+	for _, tc := range testCases {
+		w, h := slotSize(a4Width, a4Height, tc.pagesPerSheet)
+		if math.Abs(w-tc.width) > 0.01 || math.Abs(h-tc.height) > 0.01 {
+			t.Errorf("For PagesPerSheet=%d, expected slot %.2fx%.2f, got %.2fx%.2f",
+				tc.pagesPerSheet, tc.width, tc.height, w, h)
+		}
+	}
+}
+
+func TestComputePlacementsModes(t *testing.T) {
+	// A Letter page into an A5 slot
+	pages := []PageInfo{{Number: 1, Width: 612, Height: 792}}
+	slotW, slotH := 420.94, 595.28
+
+	fit := computePlacements(pages, slotW, slotH, ScaleFit, "c")[0]
+	if math.Abs(fit.Scale-slotW/612) > 0.0001 {
+		t.Errorf("Expected fit scale %.4f, got %.4f", slotW/612, fit.Scale)
+	}
+	if fit.Cropped {
+		t.Error("Fit placement should never be cropped")
+	}
+	if fit.OffsetX != 0 || fit.OffsetY <= 0 {
+		t.Errorf("Expected centered fit with vertical free space, got offset %.2f,%.2f", fit.OffsetX, fit.OffsetY)
+	}
+
+	fill := computePlacements(pages, slotW, slotH, ScaleFill, "c")[0]
+	if math.Abs(fill.Scale-slotH/792) > 0.0001 {
+		t.Errorf("Expected fill scale %.4f, got %.4f", slotH/792, fill.Scale)
+	}
+	if !fill.Cropped {
+		t.Error("Fill placement of a Letter page into A5 should be cropped")
+	}
+
+	actual := computePlacements(pages, slotW, slotH, ScaleActual, "bl")[0]
+	if actual.Scale != 1 || actual.OffsetX != 0 || actual.OffsetY != 0 {
+		t.Errorf("Expected actual size anchored bottom left, got %+v", actual)
+	}
+}
+
+func TestComputePlacementsNormalize(t *testing.T) {
+	pages := []PageInfo{
+		{Number: 1, Width: 400, Height: 600},
+		{Number: 2, Width: 380, Height: 570},
+		{Number: 3, Width: 500, Height: 750},
+	}
+
+	first := computePlacements(pages, 200, 300, ScaleFirst, "c")
+	// Page 2 is normalized to 400x600 and then fitted into the slot
+	if math.Abs(first[1].Scale*380-200) > 0.01 {
+		t.Errorf("Expected page 2 to span the slot width after normalizing, got scale %.4f", first[1].Scale)
+	}
+
+	largest := computePlacements(pages, 200, 300, ScaleLargest, "c")
+	if math.Abs(largest[2].Scale-0.4) > 0.0001 {
+		t.Errorf("Expected largest page scale 0.4, got %.4f", largest[2].Scale)
+	}
+}
+
+func TestAlignOffset(t *testing.T) {
+	testCases := []struct {
+		align string
+		x, y  float64
+	}{
+		{"c", 5, 10},
+		{"tl", 0, 20},
+		{"tr", 10, 20},
+		{"bc", 5, 0},
+		{"r", 10, 10},
+	}
+
+	for _, tc := range testCases {
+		x, y := alignOffset(10, 20, tc.align)
+		if x != tc.x || y != tc.y {
+			t.Errorf("For align=%s, expected offset %.0f,%.0f, got %.0f,%.0f", tc.align, tc.x, tc.y, x, y)
+		}
+	}
+}
+
+func TestOddSizedPages(t *testing.T) {
+	pages := []PageInfo{
+		{Number: 1, Width: 595.28, Height: 841.89},
+		{Number: 2, Width: 595.5, Height: 842.0}, // Within tolerance
+		{Number: 3, Width: 612, Height: 792},
+		{Number: 4, Width: 595.28, Height: 841.89},
+	}
+
+	odd := oddSizedPages(pages)
+	if len(odd) != 1 || odd[0].Number != 3 {
+		t.Errorf("Expected only page 3 to differ from the majority, got %+v", odd)
+	}
+}
+
+func TestMajoritySizeWithRotate(t *testing.T) {
+	// Portrait pages, two of them stored as landscape with /Rotate
+	pages := []PageInfo{
+		{Number: 1, Width: 595.28, Height: 841.89},
+		{Number: 2, Width: 841.89, Height: 595.28, Rotate: 90},
+		{Number: 3, Width: 841.89, Height: 595.28, Rotate: 270},
+		{Number: 4, Width: 841.89, Height: 595.28},
+		{Number: 5, Width: 841.89, Height: 595.28},
+	}
+
+	width, height := majoritySize(pages)
+	if width != 595.28 || height != 841.89 {
+		t.Errorf("Expected the portrait majority 595.28x841.89, got %.2fx%.2f", width, height)
+	}
+	odd := oddSizedPages(pages)
+	if len(odd) != 2 || odd[0].Number != 4 || odd[1].Number != 5 {
+		t.Errorf("Expected the unrotated landscape pages 4 and 5 to differ, got %+v", odd)
+	}
+}
+
+func TestPageSizeLine(t *testing.T) {
+	tests := []struct {
+		page     PageInfo
+		expected string
+	}{
+		{PageInfo{Number: 4, Width: 841.89, Height: 595.28}, "page 4: 841.89x595.28"},
+		{PageInfo{Number: 2, Width: 841.89, Height: 595.28, Rotate: 90}, "page 2: 595.28x841.89 (841.89x595.28 with /Rotate 90)"},
+	}
+
+	for _, test := range tests {
+		if line := pageSizeLine(test.page); line != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, line)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestSignatureName(t *testing.T) {
+	testCases := []struct {
+		signature int
+		style     string
+		expected  string
+	}{
+		{1, LabelsLetters, "A"},
+		{9, LabelsLetters, "I"},
+		{10, LabelsLetters, "K"}, // J is skipped
+		{23, LabelsLetters, "Z"},
+		{24, LabelsLetters, "AA"},
+		{3, LabelsNumbers, "3"},
+	}
+
+	for _, tc := range testCases {
+		name := signatureName(tc.signature, tc.style)
+		if name != tc.expected {
+			t.Errorf("For signature %d (%s), expected %s, got %s", tc.signature, tc.style, tc.expected, name)
+		}
+	}
+
+	if leafName(2, LabelsLetters) != "B2" || leafName(2, LabelsNumbers) != "2*" {
+		t.Errorf("Unexpected leaf names %s and %s", leafName(2, LabelsLetters), leafName(2, LabelsNumbers))
+	}
+}
+
+func TestSignatureLabels(t *testing.T) {
+	labels := signatureLabels(96, 32, LabelsLetters, true, "MYBOOK", "LTR")
+	if len(labels) != 6 {
+		t.Fatalf("Expected 3 signatures with 2 labels each, got %d", len(labels))
+	}
+
+	expected := []struct {
+		page int
+		text string
+	}{
+		{1, "A"}, {3, "A2"}, {33, "B"}, {35, "B2"}, {65, "C"}, {67, "C2"},
+	}
+	for i, e := range expected {
+		if labels[i].Page != e.page || labels[i].Text != e.text {
+			t.Errorf("Expected label %s on page %d, got %s on page %d", e.text, e.page, labels[i].Text, labels[i].Page)
+		}
+	}
+
+	if labels[0].Title != "MYBOOK" || labels[1].Title != "" {
+		t.Errorf("Expected the title on the first leaf only, got %q and %q", labels[0].Title, labels[1].Title)
+	}
+	if labels[0].Position != "br" {
+		t.Errorf("Expected LTR labels in the bottom right corner, got %s", labels[0].Position)
+	}
+
+	rtl := signatureLabels(96, 32, LabelsNumbers, false, "", "RTL")
+	if len(rtl) != 3 || rtl[0].Position != "bl" || rtl[2].Text != "3" {
+		t.Errorf("Unexpected RTL labels: %+v", rtl)
+	}
+
+	if labels := signatureLabels(96, 32, LabelsOff, true, "", "LTR"); labels != nil {
+		t.Errorf("Expected no labels when off, got %+v", labels)
+	}
+}
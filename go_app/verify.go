@@ -0,0 +1,595 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pageMarkerPrefix starts the hidden text that identifies every prepared page
+const pageMarkerPrefix = "bm:"
+
+// Slots of a booklet page, either side of the fold
+const (
+	slotLeft  = 0
+	slotRight = 1
+)
+
+// Folio is one folded piece of paper, a cell of a printed sheet after cutting
+//
+// Front and Back hold the page markers left and right of the fold, 0 for a blank.
+type Folio struct {
+	Signature int
+	Sheet     int // Physical sheet within the signature
+	Cell      int // n-up cell on the sheet
+	Source    string
+	Front     [2]int
+	Back      [2]int
+}
+
+// MarkedFile is an imposed PDF with the page markers found in each n-up cell of each side
+type MarkedFile struct {
+	Name  string
+	Sides [][][2]int
+}
+
+// ReadPage is a page of the folded book and where it was printed
+type ReadPage struct {
+	Marker int
+	Folio  Folio
+	Back   bool
+	Slot   int
+}
+
+// writePageMarkers stamps every prepared page with its hidden source page marker
+func writePageMarkers(pdfFile string, order []int) error {
+	fmt.Printf("Writing hidden page markers to %s, %d pages\n", pdfFile, len(order))
+
+	// In a real implementation, this would stamp invisible "bm:<page>" text (render mode 3) on each page
+	return nil
+}
+
+// imposeBookletSides lays out the prepared pages on booklet sides as createBooklet does
+//
+// Every signature is a saddle-stitched stack of folios: the outer folio carries
+// the first and last page. RTL books are reversed before imposition. A short
+// last signature is padded with blanks to whole folios.
+func imposeBookletSides(order []int, sidesPerSignature int, direction string) [][2]int {
+	pages := make([]int, len(order))
+	copy(pages, order)
+	if direction == "RTL" {
+		for i, j := 0, len(pages)-1; i < j; i, j = i+1, j-1 {
+			pages[i], pages[j] = pages[j], pages[i]
+		}
+	}
+
+	perSignature := 2 * sidesPerSignature
+	if perSignature <= 0 {
+		perSignature = len(pages)
+	}
+
+	var sides [][2]int
+	for start := 0; start < len(pages); start += perSignature {
+		end := start + perSignature
+		if end > len(pages) {
+			end = len(pages)
+		}
+		signature := append([]int(nil), pages[start:end]...)
+		for len(signature)%4 != 0 {
+			signature = append(signature, 0)
+		}
+
+		n := len(signature)
+		for k := 0; k < n/4; k++ {
+			sides = append(sides,
+				[2]int{signature[n-1-2*k], signature[2*k]},
+				[2]int{signature[2*k+1], signature[n-2-2*k]})
+		}
+	}
+	return sides
+}
+
+// bookletFolios pairs the fronts and backs of an imposed booklet into folios
+func bookletFolios(bookletFile string, sides [][2]int) []Folio {
+	folios := make([]Folio, 0, len(sides)/2)
+	for i := 0; i+1 < len(sides); i += 2 {
+		folios = append(folios, Folio{
+			Source: fmt.Sprintf("%s page %d", bookletFile, i+1),
+			Front:  sides[i],
+			Back:   sides[i+1],
+		})
+	}
+	return folios
+}
+
+// printedFolios simulates printing the print-ready files and cutting the sheets into folios
+//
+// Manual duplex prints the front file, then feeds the stack again for the back
+// file: face up output is fed last sheet first, face down output in order.
+func printedFolios(files []MarkedFile, opts PrintOptions) []Folio {
+	var folios, run []Folio
+	cells := 1
+	addSheet := func(source string, front, back [][2]int) {
+		if opts.Repeat && len(front) > 1 {
+			// Every cell is a copy of the first; repeatProblems checks they match
+			front, back = front[:1], back[:min(1, len(back))]
+		}
+		cells = len(front)
+		for cell := range front {
+			folio := Folio{Source: source, Front: front[cell]}
+			if cell < len(back) {
+				folio.Back = back[cell]
+			}
+			run = append(run, folio)
+		}
+	}
+	// Cut-and-stack sheets are cut as one pile per file and the stacks put on top of each other
+	endRun := func() {
+		if opts.CutStack {
+			run = stackOrder(run, cells)
+		}
+		folios = append(folios, run...)
+		run = nil
+	}
+
+	if opts.Mode == DuplexManual || opts.Mode == "" {
+		for i := 0; i+1 < len(files); i += 2 {
+			fronts, backs := files[i], files[i+1]
+			sheets := len(fronts.Sides)
+			printedBacks := make([][][2]int, sheets)
+			for feed, side := range backs.Sides {
+				sheet := feed
+				if opts.Face != FaceDown {
+					sheet = sheets - 1 - feed
+				}
+				if sheet >= 0 && sheet < sheets {
+					printedBacks[sheet] = side
+				}
+			}
+			for sheet, front := range fronts.Sides {
+				addSheet(fmt.Sprintf("%s sheet %d", fronts.Name, sheet+1), front, printedBacks[sheet])
+			}
+			endRun()
+		}
+		return folios
+	}
+
+	// Interleaved duplex files alternate the front and back of every sheet
+	for _, file := range files {
+		for i := 0; i < len(file.Sides); i += 2 {
+			var back [][2]int
+			if i+1 < len(file.Sides) {
+				back = file.Sides[i+1]
+			}
+			addSheet(fmt.Sprintf("%s sheet %d", file.Name, i/2+1), file.Sides[i], back)
+		}
+		endRun()
+	}
+	return folios
+}
+
+// numberFolios records the signature, sheet and cell of every folio in stacking order
+func numberFolios(folios []Folio, foliosPerSignature, pagesPerSheet int) {
+	if pagesPerSheet <= 0 {
+		pagesPerSheet = 1
+	}
+	for i := range folios {
+		within := i
+		if foliosPerSignature > 0 {
+			folios[i].Signature = i/foliosPerSignature + 1
+			within = i % foliosPerSignature
+		}
+		folios[i].Sheet = within/pagesPerSheet + 1
+		folios[i].Cell = within%pagesPerSheet + 1
+	}
+}
+
+// foldReadingOrder nests the folios of every signature, folds them and reads the book
+//
+// An LTR book reads the right half of each front and the left half of each
+// back on the way in, then the other halves on the way out; RTL mirrors the
+// halves. RTL signatures come off the printer last signature first, as the
+// prepared pages are reversed before imposition, so they are gathered in
+// reverse.
+func foldReadingOrder(folios []Folio, foliosPerSignature int, direction string) []ReadPage {
+	if foliosPerSignature <= 0 {
+		foliosPerSignature = len(folios)
+	}
+
+	var signatures [][]Folio
+	for start := 0; start < len(folios); start += foliosPerSignature {
+		end := start + foliosPerSignature
+		if end > len(folios) {
+			end = len(folios)
+		}
+		signatures = append(signatures, folios[start:end])
+	}
+
+	inner, outer := slotRight, slotLeft
+	if direction == "RTL" {
+		inner, outer = slotLeft, slotRight
+		for i, j := 0, len(signatures)-1; i < j; i, j = i+1, j-1 {
+			signatures[i], signatures[j] = signatures[j], signatures[i]
+		}
+	}
+
+	var read []ReadPage
+	for _, signature := range signatures {
+		for _, folio := range signature {
+			read = append(read,
+				ReadPage{Marker: folio.Front[inner], Folio: folio, Slot: inner},
+				ReadPage{Marker: folio.Back[outer], Folio: folio, Back: true, Slot: outer})
+		}
+		for k := len(signature) - 1; k >= 0; k-- {
+			folio := signature[k]
+			read = append(read,
+				ReadPage{Marker: folio.Back[inner], Folio: folio, Back: true, Slot: inner},
+				ReadPage{Marker: folio.Front[outer], Folio: folio, Slot: outer})
+		}
+	}
+	return read
+}
+
+// describeReadPage names the sheet, side and slot a page was printed on
+func describeReadPage(page ReadPage) string {
+	side, slot := "front", "left"
+	if page.Back {
+		side = "back"
+	}
+	if page.Slot == slotRight {
+		slot = "right"
+	}
+	return fmt.Sprintf("signature %d, sheet %d, cell %d, %s %s slot (%s)",
+		page.Folio.Signature, page.Folio.Sheet, page.Folio.Cell, side, slot, page.Folio.Source)
+}
+
+// checkReadingOrder compares the folded book with the expected prepared page order
+//
+// Blanks are compared too when the page counts match; otherwise only the
+// source pages have to follow each other.
+func checkReadingOrder(read []ReadPage, expected []int) []string {
+	var problems []string
+	if len(read) == len(expected) {
+		for i, page := range read {
+			if page.Marker != expected[i] {
+				problems = append(problems, fmt.Sprintf("book page %d shows %s where %s is expected, printed on %s",
+					i+1, markerName(page.Marker), markerName(expected[i]), describeReadPage(page)))
+			}
+		}
+		return problems
+	}
+
+	var want []int
+	for _, page := range expected {
+		if page > 0 {
+			want = append(want, page)
+		}
+	}
+	i := 0
+	for _, page := range read {
+		if page.Marker == 0 {
+			continue
+		}
+		if i >= len(want) {
+			problems = append(problems, fmt.Sprintf("extra %s printed on %s", markerName(page.Marker), describeReadPage(page)))
+			continue
+		}
+		if page.Marker != want[i] {
+			problems = append(problems, fmt.Sprintf("%s shows where %s is expected, printed on %s",
+				markerName(page.Marker), markerName(want[i]), describeReadPage(page)))
+		}
+		i++
+	}
+	if i < len(want) {
+		problems = append(problems, fmt.Sprintf("%d source pages are missing, starting at page %d", len(want)-i, want[i]))
+	}
+	return problems
+}
+
+// markerName names a page marker in a report
+func markerName(marker int) string {
+	if marker == 0 {
+		return "a blank"
+	}
+	return fmt.Sprintf("page %d", marker)
+}
+
+// markerStreamFile returns the file that keeps the marker text of an imposed PDF
+func markerStreamFile(pdfFile string) string {
+	return pdfFile + ".bm"
+}
+
+// markerToken is the marker text of one slot: "bm:<page>", "-" for a blank page or "x" for an unprinted cell
+func markerToken(page int) string {
+	switch {
+	case page > 0:
+		return fmt.Sprintf("%s%d", pageMarkerPrefix, page)
+	case page == 0:
+		return "-"
+	}
+	return "x"
+}
+
+// parseMarkerToken reads the page of a slot's marker text
+func parseMarkerToken(token string) (int, error) {
+	switch token {
+	case "-":
+		return 0, nil
+	case "x":
+		return -1, nil
+	}
+	page, err := strconv.Atoi(strings.TrimPrefix(token, pageMarkerPrefix))
+	if err != nil || !strings.HasPrefix(token, pageMarkerPrefix) || page <= 0 {
+		return 0, fmt.Errorf("invalid page marker %q", token)
+	}
+	return page, nil
+}
+
+// markerLayout lists the options that decide where an imposed file puts its pages
+//
+// opts is nil for the booklet; the print-ready files also depend on the duplex
+// options. The list is stored with the markers, so verify can refuse to read
+// output with options it wasn't given.
+func markerLayout(config *BookletConfig, opts *PrintOptions) []string {
+	layout := "booklet"
+	switch {
+	case config.Imposition != "":
+		layout = "imposition:" + config.Imposition
+	case config.PerfectBinding:
+		layout = "perfect"
+	}
+	fields := []string{
+		"layout=" + layout,
+		fmt.Sprintf("pages=%d", config.PagesPerSheet),
+		fmt.Sprintf("sections=%d", config.Sections),
+		"binding=" + config.Binding,
+	}
+	if opts == nil {
+		return fields
+	}
+
+	fields = append(fields, "duplex="+opts.Mode)
+	if opts.Mode == DuplexManual || opts.Mode == "" {
+		fields = append(fields, "face="+opts.Face)
+	} else {
+		fields = append(fields, "scope="+opts.Scope)
+	}
+	return append(fields, fmt.Sprintf("cut-stack=%t", opts.CutStack), fmt.Sprintf("repeat=%t", opts.Repeat))
+}
+
+// checkMarkerLayout reports the options an imposed file was written with that differ from the expected ones
+func checkMarkerLayout(pdfFile string, found, expected []string) error {
+	written := make(map[string]string, len(found))
+	for _, field := range found {
+		key, value, _ := strings.Cut(field, "=")
+		written[key] = value
+	}
+
+	var differences []string
+	for _, field := range expected {
+		key, value, _ := strings.Cut(field, "=")
+		if written[key] != value {
+			differences = append(differences, fmt.Sprintf("%s=%s, not %s", key, written[key], value))
+		}
+	}
+	if len(differences) > 0 {
+		return fmt.Errorf("%s was imposed with %s; pass the options it was made with", pdfFile, strings.Join(differences, ", "))
+	}
+	return nil
+}
+
+// writeMarkerStream records the layout and the marker text of every side of an imposed PDF
+//
+// The imposed pages carry their markers as invisible text; the stream keeps
+// the same text, one line per PDF page with the n-up cells left to right, top
+// to bottom, so verify reads what was written rather than what should have been.
+// The first line, starting with #, lists the layout options.
+func writeMarkerStream(pdfFile string, layout []string, sides [][][2]int) error {
+	var text strings.Builder
+	text.WriteString("# " + strings.Join(layout, " ") + "\n")
+	for _, side := range sides {
+		cells := make([]string, len(side))
+		for i, cell := range side {
+			cells[i] = markerToken(cell[slotLeft]) + " " + markerToken(cell[slotRight])
+		}
+		text.WriteString(strings.Join(cells, " | ") + "\n")
+	}
+	return os.WriteFile(markerStreamFile(pdfFile), []byte(text.String()), 0644)
+}
+
+// readMarkerStream reads the layout and the marker text of every side of an imposed PDF
+func readMarkerStream(pdfFile string) ([]string, [][][2]int, error) {
+	data, err := os.ReadFile(markerStreamFile(pdfFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("no page markers for %s: %w", pdfFile, err)
+	}
+
+	var layout []string
+	var sides [][][2]int
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		layout = strings.Fields(strings.TrimPrefix(lines[0], "#"))
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		var side [][2]int
+		for _, text := range strings.Split(line, " | ") {
+			tokens := strings.Fields(text)
+			if len(tokens) != 2 {
+				return nil, nil, fmt.Errorf("%s page %d: expected two slot markers per cell, got %q", pdfFile, i+1, text)
+			}
+			var cell [2]int
+			for slot, token := range tokens {
+				if cell[slot], err = parseMarkerToken(token); err != nil {
+					return nil, nil, fmt.Errorf("%s page %d: %w", pdfFile, i+1, err)
+				}
+			}
+			side = append(side, cell)
+		}
+		sides = append(sides, side)
+	}
+	return layout, sides, nil
+}
+
+// writeBookletMarkers records the layout and the markers of the imposed booklet, one booklet side per page
+func writeBookletMarkers(bookletFile string, layout []string, sides [][2]int) error {
+	fmt.Printf("Recording page markers of %s, %d sides\n", bookletFile, len(sides))
+	stream := make([][][2]int, len(sides))
+	for i, side := range sides {
+		stream[i] = [][2]int{side}
+	}
+	return writeMarkerStream(bookletFile, layout, stream)
+}
+
+// readBookletMarkers reads the page markers from every side of the imposed booklet
+//
+// The booklet must have been imposed with the given layout; nil skips the check.
+func readBookletMarkers(bookletFile string, layout []string) ([][2]int, error) {
+	fmt.Printf("Reading page markers from %s\n", bookletFile)
+	written, stream, err := readMarkerStream(bookletFile)
+	if err == nil && layout != nil {
+		err = checkMarkerLayout(bookletFile, written, layout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sides := make([][2]int, len(stream))
+	for i, side := range stream {
+		if len(side) != 1 {
+			return nil, fmt.Errorf("%s page %d has %d cells, expected one booklet side", bookletFile, i+1, len(side))
+		}
+		sides[i] = side[0]
+	}
+	return sides, nil
+}
+
+// markPrintFiles fills the n-up cells of the planned print-ready files with the booklet sides they print
+func markPrintFiles(dir string, plan []PrintFile, sides [][2]int) []MarkedFile {
+	files := make([]MarkedFile, len(plan))
+	for i, file := range plan {
+		files[i].Name = filepath.Join(dir, file.Name)
+		for _, side := range file.Sides {
+			cells := make([][2]int, len(side.Pages))
+			for j, page := range side.Pages {
+				cells[j] = emptyCell
+				if page > 0 && page <= len(sides) {
+					cells[j] = sides[page-1]
+				}
+			}
+			files[i].Sides = append(files[i].Sides, cells)
+		}
+	}
+	return files
+}
+
+// writePrintReadyMarkers records the layout and the markers of every print-ready file
+func writePrintReadyMarkers(dir string, layout []string, files []MarkedFile) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		err = writeMarkerStream(file.Name, layout, file.Sides)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPrintReadyMarkers reads the page markers of the planned files from the print-ready folder
+//
+// Every file must have been written with the given layout.
+func readPrintReadyMarkers(dir string, plan []PrintFile, layout []string) ([]MarkedFile, error) {
+	fmt.Printf("Reading page markers from %s\n", dir)
+	files := make([]MarkedFile, len(plan))
+	for i, file := range plan {
+		files[i].Name = filepath.Join(dir, file.Name)
+		written, sides, err := readMarkerStream(files[i].Name)
+		if err == nil {
+			err = checkMarkerLayout(files[i].Name, written, layout)
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[i].Sides = sides
+	}
+	return files, nil
+}
+
+// verifyBooklet simulates printing, stacking and folding the imposed output and checks its reading order
+//
+// The booklet file is checked when printReady is empty, otherwise the print-ready folder.
+func verifyBooklet(config *BookletConfig, bookletFile, printReady string) ([]string, error) {
+	err := loadPrinterSettings(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load printer profile: %w", err)
+	}
+	err = resolveBinding(config)
+	if err != nil {
+		return nil, err
+	}
+	// Only the -pages booklets are folded; the markers of other layouts only record how they were made
+	switch {
+	case config.Imposition != "":
+		return nil, fmt.Errorf("verify folds the -pages booklet layouts, not imposition templates like %s", config.Imposition)
+	case config.PerfectBinding:
+		return nil, fmt.Errorf("verify folds signatures, perfect-bound leaves have none")
+	}
+
+	pages, err := readPageInfo(config.InputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page info: %w", err)
+	}
+	order, _, _, err := bookletPageOrder(config, len(pages))
+	if err != nil {
+		return nil, err
+	}
+	sidesPerSignature := signaturePageCount(config.Sections, config.PagesPerSheet)
+
+	var folios []Folio
+	var problems []string
+	cells := config.PagesPerSheet
+	if printReady == "" {
+		sides, err := readBookletMarkers(bookletFile, markerLayout(config, nil))
+		if err != nil {
+			return nil, err
+		}
+		folios = bookletFolios(bookletFile, sides)
+	} else {
+		opts := PrintOptions{Mode: config.Duplex, Scope: config.DuplexScope, Face: config.Face, Binding: config.Binding,
+			CutStack: config.CutStack, Repeat: config.Repeat}
+		plan := planPrintFiles(bookletFile, len(order)/2, sidesPerSignature, config.PagesPerSheet, opts)
+		files, err := readPrintReadyMarkers(printReady, plan, markerLayout(config, &opts))
+		if err != nil {
+			return nil, err
+		}
+		folios = printedFolios(files, opts)
+		if config.Repeat {
+			// The first copy is folded; the other cells must show the same pages
+			problems = repeatProblems(files)
+			cells = 1
+		}
+	}
+
+	foliosPerSignature := sidesPerSignature / 2
+	numberFolios(folios, foliosPerSignature, cells)
+	read := foldReadingOrder(folios, foliosPerSignature, bindingDirection(config.Binding))
+	problems = append(problems, checkReadingOrder(read, order)...)
+
+	if len(problems) == 0 {
+		fmt.Printf("Verified: the folded book reads its %d source pages in order (%s, %d folios)\n",
+			len(pages), bindingDirection(config.Binding), len(folios))
+		return nil, nil
+	}
+	for _, problem := range problems {
+		fmt.Printf("Misplaced: %s\n", problem)
+	}
+	return problems, nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sequentialOrder(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i + 1
+	}
+	return order
+}
+
+func TestImposeBookletSides(t *testing.T) {
+	sides := imposeBookletSides(sequentialOrder(8), 4, "LTR")
+	expected := [][2]int{{8, 1}, {2, 7}, {6, 3}, {4, 5}}
+	if !reflect.DeepEqual(sides, expected) {
+		t.Errorf("Expected %v, got %v", expected, sides)
+	}
+
+	// A short last signature is padded to whole folios
+	sides = imposeBookletSides(sequentialOrder(10), 4, "LTR")
+	if len(sides) != 6 || sides[4] != [2]int{0, 9} {
+		t.Errorf("Expected a padded last folio, got %v", sides)
+	}
+}
+
+func TestFoldReadingOrder(t *testing.T) {
+	tests := []struct {
+		direction string
+		pages     int
+		sides     int
+	}{
+		{"LTR", 8, 4},
+		{"LTR", 32, 8},
+		{"RTL", 8, 4},
+		{"RTL", 48, 16},
+	}
+
+	for _, test := range tests {
+		order := sequentialOrder(test.pages)
+		folios := bookletFolios("booklet.pdf", imposeBookletSides(order, test.sides, test.direction))
+		numberFolios(folios, test.sides/2, 1)
+		read := foldReadingOrder(folios, test.sides/2, test.direction)
+		if problems := checkReadingOrder(read, order); len(problems) > 0 {
+			t.Errorf("%s, %d pages: unexpected problems %v", test.direction, test.pages, problems)
+		}
+	}
+}
+
+func TestPrintedFolios(t *testing.T) {
+	order := sequentialOrder(32)
+	sides := imposeBookletSides(order, 16, "LTR")
+
+	for _, opts := range []PrintOptions{
+		{Mode: DuplexManual, Face: FaceUp},
+		{Mode: DuplexManual, Face: FaceDown},
+		{Mode: DuplexLong, Scope: DuplexPerSignature},
+		{Mode: DuplexShort, Scope: DuplexPerBook},
+	} {
+		files := markPrintFiles("print_ready", planPrintFiles("booklet.pdf", len(sides), 16, 2, opts), sides)
+		folios := printedFolios(files, opts)
+		numberFolios(folios, 8, 2)
+		read := foldReadingOrder(folios, 8, "LTR")
+		if problems := checkReadingOrder(read, order); len(problems) > 0 {
+			t.Errorf("%+v: unexpected problems %v", opts, problems)
+		}
+	}
+}
+
+func TestVerifyReportsMisplacedSheet(t *testing.T) {
+	order := sequentialOrder(16)
+	sides := imposeBookletSides(order, 8, "LTR")
+	opts := PrintOptions{Mode: DuplexManual, Face: FaceUp}
+	files := markPrintFiles("print_ready", planPrintFiles("booklet.pdf", len(sides), 8, 1, opts), sides)
+
+	// Feed the back stack in the wrong order, as if it was not reversed
+	backs := files[1].Sides
+	for i, j := 0, len(backs)-1; i < j; i, j = i+1, j-1 {
+		backs[i], backs[j] = backs[j], backs[i]
+	}
+
+	folios := printedFolios(files, opts)
+	numberFolios(folios, 4, 1)
+	problems := checkReadingOrder(foldReadingOrder(folios, 4, "LTR"), order)
+	if len(problems) == 0 {
+		t.Fatal("Expected problems for a misfed back stack, got none")
+	}
+	if !strings.Contains(problems[0], "back") || !strings.Contains(problems[0], "sheet") {
+		t.Errorf("Expected the problem to name the sheet and side, got %q", problems[0])
+	}
+}
+
+func TestVerifyReportsSwappedSlot(t *testing.T) {
+	order := sequentialOrder(8)
+	sides := imposeBookletSides(order, 4, "LTR")
+	sides[0][0], sides[0][1] = sides[0][1], sides[0][0]
+
+	folios := bookletFolios("booklet.pdf", sides)
+	numberFolios(folios, 2, 1)
+	problems := checkReadingOrder(foldReadingOrder(folios, 2, "LTR"), order)
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems for a swapped slot, got %v", problems)
+	}
+	if !strings.Contains(problems[0], "book page 1 shows page 8") || !strings.Contains(problems[0], "front right slot") {
+		t.Errorf("Unexpected problem %q", problems[0])
+	}
+}
+
+func TestCheckReadingOrderMissingPages(t *testing.T) {
+	read := []ReadPage{{Marker: 1}, {Marker: 0}, {Marker: 2}}
+	problems := checkReadingOrder(read, []int{1, 2, 3, 0, 0})
+	if len(problems) != 1 || !strings.Contains(problems[0], "1 source pages are missing") {
+		t.Errorf("Expected a missing page problem, got %v", problems)
+	}
+}
+
+// swapMarkerLines swaps two pages of a marker stream, as if two sides were printed in each other's place
+func swapMarkerLines(t *testing.T, pdfFile string, i, j int) {
+	t.Helper()
+	data, err := os.ReadFile(markerStreamFile(pdfFile))
+	if err != nil {
+		t.Fatalf("Failed to read page markers: %v", err)
+	}
+	// The first line lists the layout
+	lines := strings.Split(string(data), "\n")
+	lines[i+1], lines[j+1] = lines[j+1], lines[i+1]
+	if err := os.WriteFile(markerStreamFile(pdfFile), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatalf("Failed to write page markers: %v", err)
+	}
+}
+
+func TestVerifyReadsWrittenMarkers(t *testing.T) {
+	bookletFile := filepath.Join(t.TempDir(), "booklet.pdf")
+	cli := &CLI{}
+	if err := cli.Run([]string{"cmd", "-i", "test.pdf", "-o", bookletFile, "-d", "LTR"}); err != nil {
+		t.Fatalf("Failed to impose the booklet: %v", err)
+	}
+	config := func() *BookletConfig {
+		return &BookletConfig{InputFile: "test.pdf", PagesPerSheet: 1, Sections: 8, AddBlank: 1, Cover: CoverNone,
+			ReadingDirection: "LTR", DuplexScope: DuplexPerSignature}
+	}
+
+	for _, printReady := range []string{"", printReadyDir} {
+		problems, err := verifyBooklet(config(), bookletFile, printReady)
+		if err != nil || len(problems) > 0 {
+			t.Fatalf("Expected the written markers to verify, got %v (%v)", problems, err)
+		}
+	}
+
+	// A booklet side printed in the place of another
+	swapMarkerLines(t, bookletFile, 1, 3)
+	problems, err := verifyBooklet(config(), bookletFile, "")
+	if err != nil || len(problems) == 0 {
+		t.Fatalf("Expected the swapped booklet sides to be reported, got %v (%v)", problems, err)
+	}
+
+	// A back of the print-ready files fed in the wrong order
+	swapMarkerLines(t, filepath.Join(printReadyDir, "1_B_booklet_1.pdf"), 0, 1)
+	problems, err = verifyBooklet(config(), bookletFile, printReadyDir)
+	if err != nil || len(problems) == 0 {
+		t.Fatalf("Expected the swapped print-ready backs to be reported, got %v (%v)", problems, err)
+	}
+	if !strings.Contains(problems[0], "1_B_booklet_1.pdf") && !strings.Contains(problems[0], "1_F_booklet_1.pdf") {
+		t.Errorf("Expected the problem to name the first signature's files, got %q", problems[0])
+	}
+
+	// Options the output wasn't made with are refused rather than checked
+	wrongBinding := config()
+	wrongBinding.Sections = 4
+	if _, err := verifyBooklet(wrongBinding, bookletFile, ""); err == nil || !strings.Contains(err.Error(), "sections=8, not 4") {
+		t.Errorf("Expected error about the sections the booklet was imposed with, got: %v", err)
+	}
+	wrongFace := config()
+	wrongFace.Face = FaceDown
+	if _, err := verifyBooklet(wrongFace, bookletFile, printReadyDir); err == nil || !strings.Contains(err.Error(), "face=up, not down") {
+		t.Errorf("Expected error about the face the print-ready files were made with, got: %v", err)
+	}
+
+	// Output without markers can't be verified
+	if _, err := verifyBooklet(config(), filepath.Join(t.TempDir(), "other.pdf"), ""); err == nil || !strings.Contains(err.Error(), "no page markers") {
+		t.Errorf("Expected error about missing page markers, got: %v", err)
+	}
+}